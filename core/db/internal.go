@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 
 	// ORM
 	"github.com/jmoiron/sqlx"
@@ -61,7 +62,7 @@ func In_getApiList(ctx context.Context) ([]model.Api, error) {
 
 	// Execute query (get a api information)
 	var rows *sqlx.Rows
-	querySyntax := `SELECT a.api_id, a.source_id, a.api_name, a.api_alias, a.api_type, a.syntax "queryContent.syntax", a.reg_date, a.exp_date, a.status, d.options "queryContent.rawDidOptions" FROM api AS a LEFT JOIN did_option AS d ON a.api_id=d.api_id`
+	querySyntax := `SELECT a.api_id, a.source_id, a.api_name, a.api_alias, a.api_type, a.syntax "queryContent.syntax", a.reg_date, a.exp_date, a.status, d.options "queryContent.rawDidOptions", d.eval_option "queryContent.rawEvalOption" FROM api AS a LEFT JOIN did_option AS d ON a.api_id=d.api_id`
 	if dbInfo.Tracking {
 		rows, err = dbInfo.Instance.QueryxContext(ctx, querySyntax)
 	} else {
@@ -79,6 +80,12 @@ func In_getApiList(ctx context.Context) ([]model.Api, error) {
 		if err := rows.StructScan(&api); err != nil {
 			return result, err
 		}
+		// Transform k-anonymity evaluation option
+		if api.QueryContent.RawEvalOption.Valid && api.QueryContent.RawEvalOption.String != "" {
+			if err := json.Unmarshal([]byte(api.QueryContent.RawEvalOption.String), &api.QueryContent.EvalOption); err != nil {
+				return result, err
+			}
+		}
 
 		// Allocate memory to store parameters
 		api.QueryContent.ParamsKey = make([]string, 0)
@@ -53,38 +53,122 @@ type QueryContent struct {
 	ParamsValue   []interface{}             `json:"paramsValue,omitempty"`
 	RawDidOptions sql.NullString            `json:"rawDidOptions,omitempty" db:"rawDidOptions"`
 	DidOptions    map[string]AnoParamOption `json:"didOptions,omitempty"`
+	RawEvalOption sql.NullString            `json:"rawEvalOption,omitempty" db:"rawEvalOption"`
+	EvalOption    EvaluationOption          `json:"evalOption,omitempty"`
 }
 
 // evaluation result format for k-anonymity
 type Evaluation struct {
-	ApiName string `json:"apiName"`
-	Result  string `json:"result"`
-	Value   int64  `json:"value"`
+	ApiName    string               `json:"apiName"`
+	Result     string               `json:"result"`
+	Value      int64                `json:"value"`
+	Total      int64                `json:"total,omitempty"`
+	Suppressed int64                `json:"suppressed,omitempty"`
+	Sensitive  *SensitiveEvaluation `json:"sensitive,omitempty"`
+	Risk       *RiskReport          `json:"risk,omitempty"`
+}
+
+// re-identification risk report by equivalence class sizes
+type RiskReport struct {
+	SamplingFraction float64           `json:"samplingFraction"`
+	Records          int64             `json:"records"`
+	Classes          int64             `json:"classes"`
+	UniqueRecords    int64             `json:"uniqueRecords"`
+	ProsecutorMax    float64           `json:"prosecutorMax"`
+	ProsecutorAvg    float64           `json:"prosecutorAvg"`
+	JournalistMax    float64           `json:"journalistMax"`
+	JournalistAvg    float64           `json:"journalistAvg"`
+	Marketer         float64           `json:"marketer"`
+	Histogram        []ClassSizeBucket `json:"histogram"`
+}
+
+// histogram bucket of equivalence class sizes
+type ClassSizeBucket struct {
+	Range   string `json:"range"`
+	Classes int64  `json:"classes"`
+	Records int64  `json:"records"`
+}
+
+// l-diversity and t-closeness evaluation result for sensitive column
+type SensitiveEvaluation struct {
+	Column    string  `json:"column"`
+	Result    string  `json:"result"`    // targets (l, t) are satisfied or not
+	Distinct  int64   `json:"distinct"`  // distinct l-diversity (minimum count of distinct values in equivalence class)
+	Entropy   float64 `json:"entropy"`   // entropy l-diversity (minimum exp(entropy) in equivalence class)
+	Closeness float64 `json:"closeness"` // t-closeness (maximum distance from overall distribution)
+}
+
+// privacy budget (epsilon) status of consumer for differentially private API
+type PrivacyBudget struct {
+	ApiName    string  `json:"apiName"`
+	Consumer   string  `json:"consumer"`
+	Budget     float64 `json:"budget"`
+	Spent      float64 `json:"spent"`
+	Remaining  float64 `json:"remaining"`
+	WindowHour int64   `json:"windowHour"`
 }
 
 // AnoOption defines the specific anonymization option parameter format
 type AnoOption struct {
-	Fore       string `json:"fore,omitempty"`
-	Aft        string `json:"aft,omitempty"`
-	MaskChar   string `json:"maskChar,omitempty"`
-	KeepLength string `json:"keepLength,omitempty"`
-	Algorithm  string `json:"algorithm,omitempty"`
-	Position   int    `json:"position,omitempty"`
-	Unit       string `json:"unit,omitempty"`
-	Key        string `json:"key,omitempty"`
-	Digest     string `json:"digest,omitempty"`
-	Lower      string `json:"lower,omitempty"`
-	Upper      string `json:"upper,omitempty"`
-	Bin        string `json:"bin,omitempty"`
-	Linear     string `json:"linear,omitempty"`
+	Fore        string            `json:"fore,omitempty"`
+	Aft         string            `json:"aft,omitempty"`
+	MaskChar    string            `json:"maskChar,omitempty"`
+	KeepLength  string            `json:"keepLength,omitempty"`
+	Algorithm   string            `json:"algorithm,omitempty"`
+	Position    int               `json:"position,omitempty"`
+	Unit        string            `json:"unit,omitempty"`
+	Key         string            `json:"key,omitempty"`
+	Digest      string            `json:"digest,omitempty"`
+	Lower       string            `json:"lower,omitempty"`
+	Upper       string            `json:"upper,omitempty"`
+	Bin         string            `json:"bin,omitempty"`
+	Linear      string            `json:"linear,omitempty"`
+	Tweak       string            `json:"tweak,omitempty"`
+	Alphabet    string            `json:"alphabet,omitempty"`
+	Vault       string            `json:"vault,omitempty"`
+	Length      string            `json:"length,omitempty"`
+	Scope       string            `json:"scope,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Reference   string            `json:"reference,omitempty"`
+	Taxonomy    string            `json:"taxonomy,omitempty"`
+	Epsilon     string            `json:"epsilon,omitempty"`
+	Delta       string            `json:"delta,omitempty"`
+	Sensitivity string            `json:"sensitivity,omitempty"`
+	Threshold   string            `json:"threshold,omitempty"`
+	Detectors   []string          `json:"detectors,omitempty"`
+	Profile     string            `json:"profile,omitempty"`
+	Pattern     string            `json:"pattern,omitempty"`
+	Replacement string            `json:"replacement,omitempty"`
+	Groups      map[string]string `json:"groups,omitempty"`
+	PrefixV4    string            `json:"prefixV4,omitempty"`
+	PrefixV6    string            `json:"prefixV6,omitempty"`
+	Axis        string            `json:"axis,omitempty"`
+	Grid        string            `json:"grid,omitempty"`
+	KeepDomain  string            `json:"keepDomain,omitempty"`
 }
 
 // Option defines the field anonymization method parameter format
 type AnoParamOption struct {
-	Method      string    `json:"method"`
-	Options     AnoOption `json:"options"`
-	Level       int       `json:"level"`
-	Description string    `json:"description"`
+	Method      string           `json:"method"`
+	Options     AnoOption        `json:"options"`
+	Level       int              `json:"level"`
+	Description string           `json:"description"`
+	Chain       []AnoParamOption `json:"chain,omitempty"`
+	Qi          bool             `json:"qi,omitempty"`
+}
+
+// k-anonymity evaluation option for API (quasi-identifiers are columns with level > 0 or qi flag)
+type EvaluationOption struct {
+	K                  int     `json:"k"`
+	Mode               string  `json:"mode,omitempty"`               // "none" (evaluate after streaming, default), "reject", "suppress"
+	Suppression        string  `json:"suppression,omitempty"`        // "drop" (default), "blank" (blank quasi-identifiers)
+	MaxSuppressionRate float64 `json:"maxSuppressionRate,omitempty"` // 0 ~ 1 (0: unlimited), export is rejected if exceeded
+	Sensitive          string  `json:"sensitive,omitempty"`          // sensitive column for l-diversity and t-closeness
+	L                  int     `json:"l,omitempty"`                  // target l value
+	Diversity          string  `json:"diversity,omitempty"`          // "distinct" (default), "entropy" (exp(entropy) >= l)
+	T                  float64 `json:"t,omitempty"`                  // target t value
+	Distance           string  `json:"distance,omitempty"`           // "variational" (categorical, default), "ordered" (numerical or ordinal)
+	SamplingFraction   float64 `json:"samplingFraction,omitempty"`   // sample size / population size for journalist and marketer risk (default: 1)
 }
 
 // Processed log format
@@ -100,9 +184,10 @@ type Processed struct {
 
 // Processed detail format
 type ProcessedDetail struct {
-	Dsn       string `json:"dsn"`
-	KAnoPass  string `json:"kAnoPass"`
-	KAnoValue string `json:"kAnoValue"` // string of int format
-	Params    string `json:"params"`    // string of array format
-	Syntax    string `json:"syntax"`
+	Dsn       string      `json:"dsn"`
+	KAnoPass  string      `json:"kAnoPass"`
+	KAnoValue string      `json:"kAnoValue"` // string of int format
+	Params    string      `json:"params"`    // string of array format
+	Syntax    string      `json:"syntax"`
+	Risk      *RiskReport `json:"risk,omitempty"`
 }
@@ -4,17 +4,48 @@ package gen
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	// Model
 	"github.com/tovdata/privacydam-go/core/model"
 	// Util
 	"github.com/tovdata/privacydam-go/core/db"
+	"github.com/tovdata/privacydam-go/process/util/did"
 )
 
+// pattern to verify aggregate query
+var groupByPattern = regexp.MustCompile(`(?i)\bgroup\s+by\b`)
+
 // Api를 생성하는 함수입니다.
 func GenerateApi(ctx context.Context, api model.Api) error {
+	// Verify aggregate query
+	if api.Type == "aggregate" && !groupByPattern.MatchString(api.QueryContent.Syntax) {
+		return errors.New("Aggregate API requires a GROUP BY query")
+	}
+	// Verify de-identification options
+	didOptions, err := verifyDeIdentificationOptions(api.QueryContent.RawDidOptions)
+	if err != nil {
+		return err
+	}
+	// Verify k-anonymity evaluation option
+	evalOption, err := verifyEvaluationOption(api.QueryContent.RawEvalOption, didOptions)
+	if err != nil {
+		return err
+	}
+	// Verify columns of de-identification options (and sensitive column of evaluation option)
+	if err := verifyDeIdentificationColumns(ctx, api.SourceId, api.QueryContent, didOptions, evalOption.Sensitive); err != nil {
+		return err
+	}
+	// Verify taxonomies of hierarchy generalization
+	if err := verifyTaxonomies(ctx, didOptions); err != nil {
+		return err
+	}
+
 	// Get database object
 	dbInfo, err := db.GetDatabase("internal", nil)
 	if err != nil {
@@ -78,11 +109,11 @@ func GenerateApi(ctx context.Context, api model.Api) error {
 	if api.QueryContent.RawDidOptions.Valid && api.QueryContent.RawDidOptions.String != "" {
 		// Execute query (insert de-identification options)
 		var err error
-		querySyntax := `INSERT INTO did_option (api_id, options) VALUE (?, ?)`
+		querySyntax := `INSERT INTO did_option (api_id, options, eval_option) VALUE (?, ?, ?)`
 		if dbInfo.Tracking {
-			_, err = tx.ExecContext(ctx, querySyntax, insertedId, api.QueryContent.RawDidOptions)
+			_, err = tx.ExecContext(ctx, querySyntax, insertedId, api.QueryContent.RawDidOptions, api.QueryContent.RawEvalOption)
 		} else {
-			_, err = tx.Exec(querySyntax, insertedId, api.QueryContent.RawDidOptions)
+			_, err = tx.Exec(querySyntax, insertedId, api.QueryContent.RawDidOptions, api.QueryContent.RawEvalOption)
 		}
 		// Catch error
 		if err != nil {
@@ -98,6 +129,161 @@ func GenerateApi(ctx context.Context, api model.Api) error {
 	}
 }
 
+// 비식별 옵션의 유효성을 검증하는 함수입니다. 지원되지 않는 처리 방법 또는 잘못된 옵션이 있을 경우 필드별 에러 메시지를 반환합니다.
+func verifyDeIdentificationOptions(rawDidOptions sql.NullString) (map[string]model.AnoParamOption, error) {
+	if !rawDidOptions.Valid || rawDidOptions.String == "" {
+		return nil, nil
+	}
+
+	// Transform to map
+	var didOptions map[string]model.AnoParamOption
+	if err := json.Unmarshal([]byte(rawDidOptions.String), &didOptions); err != nil {
+		return nil, err
+	}
+	// Validate options
+	if validationErrors := did.ValidateOptions(didOptions); len(validationErrors) > 0 {
+		messages := make([]string, len(validationErrors))
+		for i, validationError := range validationErrors {
+			messages[i] = validationError.Error()
+		}
+		return nil, errors.New("Invalid de-identification options (" + strings.Join(messages, "; ") + ")")
+	}
+	return didOptions, nil
+}
+
+// k-익명성 평가 옵션의 유효성을 검증하는 함수입니다. 평가 옵션은 비식별 옵션과 함께 저장되므로, 비식별 옵션 없이 사용할 수 없습니다.
+func verifyEvaluationOption(rawEvalOption sql.NullString, didOptions map[string]model.AnoParamOption) (model.EvaluationOption, error) {
+	var evalOption model.EvaluationOption
+	if !rawEvalOption.Valid || rawEvalOption.String == "" {
+		return evalOption, nil
+	}
+
+	// Transform to structure
+	if err := json.Unmarshal([]byte(rawEvalOption.String), &evalOption); err != nil {
+		return evalOption, err
+	} else if len(didOptions) == 0 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (de-identification options are required)")
+	} else if evalOption.K < 0 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (k must be greater than or equal to 0)")
+	} else if evalOption.Mode != "" && evalOption.Mode != "none" && evalOption.Mode != "reject" && evalOption.Mode != "suppress" {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (mode must be one of none, reject, suppress)")
+	} else if evalOption.Suppression != "" && evalOption.Suppression != "drop" && evalOption.Suppression != "blank" {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (suppression must be one of drop, blank)")
+	} else if evalOption.MaxSuppressionRate < 0 || evalOption.MaxSuppressionRate > 1 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (maxSuppressionRate must be between 0 and 1)")
+	} else if evalOption.L < 0 || evalOption.T < 0 || evalOption.T > 1 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (l must be greater than or equal to 0, t must be between 0 and 1)")
+	} else if evalOption.Diversity != "" && evalOption.Diversity != "distinct" && evalOption.Diversity != "entropy" {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (diversity must be one of distinct, entropy)")
+	} else if evalOption.Distance != "" && evalOption.Distance != "variational" && evalOption.Distance != "ordered" {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (distance must be one of variational, ordered)")
+	} else if evalOption.SamplingFraction < 0 || evalOption.SamplingFraction > 1 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (samplingFraction must be between 0 and 1)")
+	} else if evalOption.Sensitive != "" && evalOption.L <= 0 && evalOption.T <= 0 {
+		return evalOption, errors.New("Invalid k-anonymity evaluation option (l or t is required for sensitive column)")
+	}
+	return evalOption, nil
+}
+
+// 비식별 옵션의 모든 컬럼과 평가 옵션의 민감 컬럼(sensitive)이 쿼리 결과의 컬럼으로 존재하는지 확인하는 함수입니다. 쿼리는 Source(외부 데이터베이스)에서 결과 없이(LIMIT 0) 실행됩니다.
+func verifyDeIdentificationColumns(ctx context.Context, sourceId string, content model.QueryContent, didOptions map[string]model.AnoParamOption, sensitive string) error {
+	if len(didOptions) == 0 && sensitive == "" {
+		return nil
+	}
+
+	// Get database object
+	dbInfo, err := db.GetDatabase("external", sourceId)
+	if err != nil {
+		return err
+	}
+
+	// Execute query (get columns only, parameters are replaced by null)
+	var rows *sql.Rows
+	querySyntax := `SELECT * FROM (` + strings.TrimRight(strings.TrimSpace(content.Syntax), ";") + `) AS did_columns LIMIT 0`
+	params := make([]interface{}, len(content.ParamsKey))
+	if dbInfo.Tracking {
+		rows, err = dbInfo.Instance.QueryContext(ctx, querySyntax, params...)
+	} else {
+		rows, err = dbInfo.Instance.Query(querySyntax, params...)
+	}
+	// Catch error
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Extract columns
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		exists[column] = true
+	}
+
+	// Verify
+	missing := make([]string, 0)
+	for key := range didOptions {
+		if !exists[key] {
+			missing = append(missing, key)
+		}
+	}
+	if _, duplicated := didOptions[sensitive]; sensitive != "" && !exists[sensitive] && !duplicated {
+		missing = append(missing, sensitive)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return errors.New("Columns that do not exist in query result (" + strings.Join(missing, ", ") + ")")
+	}
+	return nil
+}
+
+// 계층 일반화(hierarchy) 옵션의 분류 체계(taxonomy)가 내부 데이터베이스에 존재하는지 확인하는 함수입니다.
+func verifyTaxonomies(ctx context.Context, didOptions map[string]model.AnoParamOption) error {
+	// Collect taxonomy names
+	names := make(map[string]bool)
+	for _, option := range didOptions {
+		for _, step := range did.FlattenOption(option) {
+			if step.Method == "hierarchy" && step.Options.Taxonomy != "" {
+				names[step.Options.Taxonomy] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Get database object
+	dbInfo, err := db.GetDatabase("internal", nil)
+	if err != nil {
+		return err
+	}
+
+	// Verify
+	missing := make([]string, 0)
+	querySyntax := `SELECT COUNT(*) FROM taxonomy WHERE taxonomy_name=?`
+	for name := range names {
+		var count int64
+		if dbInfo.Tracking {
+			err = dbInfo.Instance.QueryRowContext(ctx, querySyntax, name).Scan(&count)
+		} else {
+			err = dbInfo.Instance.QueryRow(querySyntax, name).Scan(&count)
+		}
+		// Catch error
+		if err != nil {
+			return err
+		} else if count == 0 {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return errors.New("Taxonomies that do not exist (" + strings.Join(missing, ", ") + ")")
+	}
+	return nil
+}
+
 // Api 별칭에 대한 중복을 확인하는 함수입니다.
 func DuplicateCheckForAlias(ctx context.Context, alias string) error {
 	// Get database object
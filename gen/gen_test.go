@@ -0,0 +1,35 @@
+package gen
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	// Model
+	"github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestVerifyEvaluationOption(t *testing.T) {
+	didOptions := map[string]model.AnoParamOption{"age": {Method: "data_range", Qi: true}}
+	for _, test := range []struct {
+		name       string
+		evalOption string
+		didOptions map[string]model.AnoParamOption
+		expected   string
+	}{
+		{"empty", "", nil, ""},
+		{"valid", `{"k":3,"mode":"reject"}`, didOptions, ""},
+		{"without de-identification options", `{"k":3,"mode":"reject"}`, nil, "de-identification options are required"},
+		{"invalid mode", `{"k":3,"mode":"block"}`, didOptions, "mode must be one of"},
+		{"sensitive with l", `{"k":3,"sensitive":"disease","l":2}`, didOptions, ""},
+		{"sensitive with t", `{"k":3,"sensitive":"disease","t":0.3}`, didOptions, ""},
+		{"sensitive without l and t", `{"k":3,"sensitive":"disease"}`, didOptions, "l or t is required"},
+	} {
+		_, err := verifyEvaluationOption(sql.NullString{String: test.evalOption, Valid: test.evalOption != ""}, test.didOptions)
+		if test.expected == "" && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if test.expected != "" && (err == nil || !strings.Contains(err.Error(), test.expected)) {
+			t.Errorf("%s: %v, expected %q", test.name, err, test.expected)
+		}
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/tovdata/privacydam-go/core/util"
 	"github.com/tovdata/privacydam-go/process/util/auth"
 	"github.com/tovdata/privacydam-go/process/util/db"
+	"github.com/tovdata/privacydam-go/process/util/did"
+	"github.com/tovdata/privacydam-go/process/util/logger"
 )
 
 // Source(외부 데이터베이스)를 등록하기 전에 연결에 대한 테스트를 수행하는 함수입니다.
@@ -321,6 +323,55 @@ func AuthenticateAccessOnLambda(ctx context.Context, req events.APIGatewayProxyR
 	return auth.AuthenticateAccess(subCtx, tracking, opaUrl, token)
 }
 
+// 재식별(re-identification)에 대한 특수 권한을 인증하는 함수입니다. HTTP 요청 Header 내에 Token 값을 OPA 서버로 전달하고 privileged 응답을 확인합니다. (For echo framework)
+//	# Parameters
+//	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
+func AuthenticatePrivilegedAccessOnEcho(ctx echo.Context, opaUrl string) error {
+	// Get tracking status
+	tracking := util.GetTrackingStatus("processing")
+
+	// [For debug] set subsegment
+	var subCtx context.Context = ctx.Request().Context()
+	var subSegment *xray.Segment
+	if tracking {
+		subCtx, subSegment = xray.BeginSubsegment(ctx.Request().Context(), "Authentication privileged access")
+		defer subSegment.Close(nil)
+	}
+
+	// Extract access token
+	token, err := auth.ExtractAccessTokenOnEcho(ctx)
+	if err != nil {
+		return err
+	}
+	// Authenticate privileged access (using another OPA)
+	return auth.AuthenticatePrivilegedAccess(subCtx, tracking, opaUrl, token)
+}
+
+// 재식별(re-identification)에 대한 특수 권한을 인증하는 함수입니다. HTTP 요청 Header 내에 Token 값을 OPA 서버로 전달하고 privileged 응답을 확인합니다. (For aws lambda)
+//	# Parameters
+//	req (events.APIGatewayProxyRequest): AWS API Gateway proxy request
+//	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
+func AuthenticatePrivilegedAccessOnLambda(ctx context.Context, req events.APIGatewayProxyRequest, opaUrl string) error {
+	// Get tracking status
+	tracking := util.GetTrackingStatus("processing")
+
+	// [For debug] set subsegment
+	var subCtx context.Context = ctx
+	var subSegment *xray.Segment
+	if tracking {
+		subCtx, subSegment = xray.BeginSubsegment(ctx, "Authentication privileged access")
+		defer subSegment.Close(nil)
+	}
+
+	// Extract access token
+	token, err := auth.ExtractAccessTokenOnLambda(ctx, req)
+	if err != nil {
+		return err
+	}
+	// Authenticate privileged access (using another OPA)
+	return auth.AuthenticatePrivilegedAccess(subCtx, tracking, opaUrl, token)
+}
+
 // API Name를 생성하는 함수로써 Timestamp를 이용하여 API의 고유한 이름을 생성합니다.
 //	# Parameters
 //	isTemp (bool): temparary status
@@ -357,8 +408,12 @@ func ExportDataOnServer(ctx context.Context, res http.ResponseWriter, api model.
 	if api.Name == "" {
 		name = CreateApiName(true)
 	}
+	// Spend privacy budget (before any row is sent)
+	if err := reservePrivacyBudget(ctx, api); err != nil {
+		return model.Evaluation{ApiName: name, Result: "none"}, err
+	}
 	// Processing
-	return db.Ex_exportData(ctx, res, routineCount, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions)
+	return db.Ex_exportData(ctx, res, routineCount, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions, api.QueryContent.EvalOption)
 }
 
 // 데이터 반출 처리를 수행하는 함수입니다. (For aws lambda)
@@ -380,8 +435,101 @@ func ExportDataOnLambda(ctx context.Context, res *events.APIGatewayProxyResponse
 	if api.Name == "" {
 		name = CreateApiName(true)
 	}
+	// Spend privacy budget (before any row is sent)
+	if err := reservePrivacyBudget(ctx, api); err != nil {
+		return model.Evaluation{ApiName: name, Result: "none"}, err
+	}
 	// Processing
-	return db.Ex_exportDataOnLambda(ctx, res, routineCount, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions)
+	return db.Ex_exportDataOnLambda(ctx, res, routineCount, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions, api.QueryContent.EvalOption)
+}
+
+// 집계 데이터 반출 처리를 수행하는 함수입니다. (For echo framework)
+//	# Parameters
+//	res (http.ResponseWriter): writer for reponse
+//	api (model.Api): API information object for generation (type: aggregate)
+//
+//	# Response
+//	(model.Evaluation): evaluation result
+func AggregateDataOnServer(ctx context.Context, res http.ResponseWriter, api model.Api) (model.Evaluation, error) {
+	// Check api name
+	name := api.Name
+	if api.Name == "" {
+		name = CreateApiName(true)
+	}
+	// Spend privacy budget (before any row is sent)
+	if err := reservePrivacyBudget(ctx, api); err != nil {
+		return model.Evaluation{ApiName: name, Result: "none"}, err
+	}
+	// Processing
+	return db.Ex_aggregateData(ctx, res, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions)
+}
+
+// 집계 데이터 반출 처리를 수행하는 함수입니다. (For aws lambda)
+//	# Parameters
+//	res (*events.APIGatewayProxyResponse): writer for reponse (AWS API Gateway proxy response)
+//	api (model.Api): API information object for generation (type: aggregate)
+//
+//	# Response
+//	(model.Evaluation): evaluation result
+func AggregateDataOnLambda(ctx context.Context, res *events.APIGatewayProxyResponse, api model.Api) (model.Evaluation, error) {
+	// Check api name
+	name := api.Name
+	if api.Name == "" {
+		name = CreateApiName(true)
+	}
+	// Spend privacy budget (before any row is sent)
+	if err := reservePrivacyBudget(ctx, api); err != nil {
+		return model.Evaluation{ApiName: name, Result: "none"}, err
+	}
+	// Processing
+	return db.Ex_aggregateDataOnLambda(ctx, res, name, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, api.QueryContent.DidOptions)
+}
+
+// context key to store privacy budget consumer
+type privacyConsumerKey struct{}
+
+// 프라이버시 예산을 차감할 소비자(consumer)를 context에 설정하는 함수입니다. 차분 프라이버시가 적용된 API의 데이터 반출(ExportData, AggregateData) 시 해당 소비자의 예산이 차감되며,
+// 소비자가 설정되지 않은 경우 API의 모든 요청이 하나의 예산(consumer '*')을 공유합니다.
+//	# Parameters
+//	consumer (string): consumer identifier (ex. access token subject)
+//
+//	# Response
+//	(context.Context): context with consumer
+func WithPrivacyConsumer(ctx context.Context, consumer string) context.Context {
+	return context.WithValue(ctx, privacyConsumerKey{}, consumer)
+}
+
+func getPrivacyConsumer(ctx context.Context) string {
+	if consumer, ok := ctx.Value(privacyConsumerKey{}).(string); ok && consumer != "" {
+		return consumer
+	}
+	return "*"
+}
+
+// reservePrivacyBudget spends the privacy budget of consumer before processing (no-op if API is not protected by differential privacy)
+func reservePrivacyBudget(ctx context.Context, api model.Api) error {
+	// Calculate epsilon spent by request
+	epsilon := did.CalculateEpsilon(api.QueryContent.DidOptions)
+	if epsilon == 0 {
+		return nil
+	}
+
+	// Spend privacy budget
+	_, err := db.In_spendPrivacyBudget(ctx, api.Uuid, getPrivacyConsumer(ctx), epsilon)
+	return err
+}
+
+// 소비자(consumer)의 API별 남은 프라이버시 예산을 조회하는 함수입니다.
+//	# Parameters
+//	api (model.Api): API information object
+//	consumer (string): consumer identifier (ex. access token subject)
+//
+//	# Response
+//	(model.PrivacyBudget): privacy budget status
+func GetPrivacyBudget(ctx context.Context, api model.Api, consumer string) (model.PrivacyBudget, error) {
+	budget, err := db.In_getPrivacyBudget(ctx, api.Uuid, consumer)
+	budget.ApiName = api.Name
+	return budget, err
 }
 
 // 데이터 수정(Insert, Update, Delete)에 대한 처리를 수행하는 함수입니다.
@@ -395,6 +543,96 @@ func ChangeData(ctx context.Context, api model.Api, isTest bool) (int64, error)
 	return db.Ex_changeData(ctx, api.SourceId, api.QueryContent.Syntax, api.QueryContent.ParamsValue, isTest)
 }
 
+// 가역 비식별 처리(reversible_encryption, fpe)된 값들을 원래의 값으로 복원하는 함수입니다. 특수 권한(privileged)을 가진 사용자만 처리할 수 있으며, 모든 요청은 처리 로그로 기록됩니다. (For echo framework)
+//	# Parameters
+//	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
+//	api (model.Api): API information object (contain de-identification options)
+//	column (string): column name to re-identify
+//	values ([]string): de-identified values
+//
+//	# Response
+//	([]string): re-identified values (same order as values)
+func ReIdentifyOnEcho(ctx echo.Context, opaUrl string, api model.Api, column string, values []string) ([]string, error) {
+	// Extract accessor
+	accessor := GetAccessorOnServer(ctx)
+	// Authenticate privileged access
+	if err := AuthenticatePrivilegedAccessOnEcho(ctx, opaUrl); err != nil {
+		writeReIdentifiedResult(accessor, api, column, values, "re-identification denied")
+		return nil, err
+	}
+	// Processing
+	return reIdentify(ctx.Request().Context(), accessor, api, column, values)
+}
+
+// 가역 비식별 처리(reversible_encryption, fpe)된 값들을 원래의 값으로 복원하는 함수입니다. 특수 권한(privileged)을 가진 사용자만 처리할 수 있으며, 모든 요청은 처리 로그로 기록됩니다. (For aws lambda)
+//	# Parameters
+//	req (events.APIGatewayProxyRequest): AWS API Gateway proxy request
+//	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
+//	api (model.Api): API information object (contain de-identification options)
+//	column (string): column name to re-identify
+//	values ([]string): de-identified values
+//
+//	# Response
+//	([]string): re-identified values (same order as values)
+func ReIdentifyOnLambda(ctx context.Context, req events.APIGatewayProxyRequest, opaUrl string, api model.Api, column string, values []string) ([]string, error) {
+	// Extract accessor
+	accessor := GetAccessorOnLambda(req)
+	// Authenticate privileged access
+	if err := AuthenticatePrivilegedAccessOnLambda(ctx, req, opaUrl); err != nil {
+		writeReIdentifiedResult(accessor, api, column, values, "re-identification denied")
+		return nil, err
+	}
+	// Processing
+	return reIdentify(ctx, accessor, api, column, values)
+}
+
+func reIdentify(ctx context.Context, accessor model.Accessor, api model.Api, column string, values []string) ([]string, error) {
+	// Get tracking status
+	tracking := util.GetTrackingStatus("processing")
+
+	// [For debug] set subsegment
+	if tracking {
+		_, subSegment := xray.BeginSubsegment(ctx, "Process re-identification")
+		defer subSegment.Close(nil)
+	}
+
+	// Find de-identification option for column
+	option, ok := api.QueryContent.DidOptions[column]
+	if !ok {
+		writeReIdentifiedResult(accessor, api, column, values, "re-identification failed")
+		return nil, errors.New("Not found de-identification option for column\r\n")
+	}
+	// Build re-identification function
+	reIdentifier, err := did.BuildColumnReIdentifyingFunc(option)
+	if err != nil {
+		writeReIdentifiedResult(accessor, api, column, values, "re-identification failed")
+		return nil, err
+	}
+
+	// Re-identify
+	result := make([]string, len(values))
+	for i, value := range values {
+		if result[i], err = reIdentifier(value); err != nil {
+			writeReIdentifiedResult(accessor, api, column, values, "re-identification failed")
+			return nil, err
+		}
+	}
+	writeReIdentifiedResult(accessor, api, column, values, "re-identification success")
+	return result, nil
+}
+
+func writeReIdentifiedResult(accessor model.Accessor, api model.Api, column string, values []string, result string) {
+	// Record column name and requested values as parameters
+	params := make([]interface{}, 0, len(values)+1)
+	params = append(params, column)
+	for _, value := range values {
+		params = append(params, value)
+	}
+	api.QueryContent.ParamsValue = params
+	// Write processed result
+	logger.WriteProcessedResult(accessor, api, model.Evaluation{ApiName: api.Name, Result: "none"}, result)
+}
+
 // API에 접근한 사용자의 정보를 추출하는 함수입니다. 접속 IP, UserAgent를 추출합니다.
 func GetAccessorOnServer(ctx echo.Context) model.Accessor {
 	// Define accessor struct
@@ -422,3 +660,13 @@ func GetAccessorOnServer(ctx echo.Context) model.Accessor {
 	accessor.Ip = ctx.Request().RemoteAddr
 	return accessor
 }
+
+// API에 접근한 사용자의 정보를 추출하는 함수입니다. 접속 IP, UserAgent를 추출합니다. (For aws lambda)
+//	# Parameters
+//	req (events.APIGatewayProxyRequest): AWS API Gateway proxy request
+func GetAccessorOnLambda(req events.APIGatewayProxyRequest) model.Accessor {
+	return model.Accessor{
+		Ip:        req.RequestContext.Identity.SourceIP,
+		UserAgent: req.RequestContext.Identity.UserAgent,
+	}
+}
@@ -0,0 +1,153 @@
+package process
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	// Model
+	"github.com/tovdata/privacydam-go/core/model"
+
+	// PrivacyDAM package
+	coreDB "github.com/tovdata/privacydam-go/core/db"
+)
+
+// ledgerDriver is an in-memory internal database serving privacy budget queries only
+type ledgerDriver struct {
+	mutex  sync.Mutex
+	ledger map[string]float64
+}
+
+func (d *ledgerDriver) Open(name string) (driver.Conn, error) {
+	return &ledgerConn{driver: d}, nil
+}
+
+type ledgerConn struct {
+	driver *ledgerDriver
+}
+
+func (c *ledgerConn) Prepare(query string) (driver.Stmt, error) {
+	return &ledgerStmt{conn: c, query: query}, nil
+}
+func (c *ledgerConn) Close() error              { return nil }
+func (c *ledgerConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *ledgerConn) Commit() error             { return nil }
+func (c *ledgerConn) Rollback() error           { return nil }
+
+type ledgerStmt struct {
+	conn  *ledgerConn
+	query string
+}
+
+func (s *ledgerStmt) Close() error  { return nil }
+func (s *ledgerStmt) NumInput() int { return -1 }
+func (s *ledgerStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !strings.HasPrefix(s.query, "INSERT INTO privacy_ledger") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	d := s.conn.driver
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.ledger[args[0].(string)+"/"+args[1].(string)] += args[2].(float64)
+	return driver.RowsAffected(1), nil
+}
+func (s *ledgerStmt) Query(args []driver.Value) (driver.Rows, error) {
+	switch {
+	case strings.Contains(s.query, "FROM privacy_budget"):
+		return &ledgerRows{columns: []string{"budget", "window_hour"}}, nil
+	case strings.Contains(s.query, "FROM privacy_ledger"):
+		d := s.conn.driver
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		spent := d.ledger[args[0].(string)+"/"+args[1].(string)]
+		return &ledgerRows{columns: []string{"spent"}, values: [][]driver.Value{{spent}}}, nil
+	default:
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+}
+
+type ledgerRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *ledgerRows) Columns() []string { return r.columns }
+func (r *ledgerRows) Close() error      { return nil }
+func (r *ledgerRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var ledger = &ledgerDriver{ledger: make(map[string]float64)}
+
+func init() {
+	sql.Register("privacy_ledger", ledger)
+}
+
+func TestExportRejectedOverPrivacyBudget(t *testing.T) {
+	os.Setenv("TRACK_A_PROCESSING", "false")
+	os.Setenv("TRACK_A_DATABASE", "false")
+	os.Setenv("PRIVACY_BUDGET", "1")
+	defer os.Unsetenv("PRIVACY_BUDGET")
+	if err := coreDB.CreateConnectionPool(context.Background(), model.Source{Type: "privacy_ledger"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	api := model.Api{
+		Uuid: "1",
+		Name: "noised",
+		QueryContent: model.QueryContent{
+			DidOptions: map[string]model.AnoParamOption{
+				"salary": {Method: "noise", Options: model.AnoOption{Algorithm: "laplace", Epsilon: "0.6", Sensitivity: "1"}},
+			},
+		},
+	}
+	ctx := WithPrivacyConsumer(context.Background(), "alice")
+
+	// First request spends the budget (export itself fails because no source is connected)
+	if _, err := ExportDataOnServer(ctx, httptest.NewRecorder(), api); err == nil || strings.Contains(err.Error(), "Privacy budget exhausted") {
+		t.Fatalf("first request: %v", err)
+	}
+	if spent := ledger.ledger["1/alice"]; spent != 0.6 {
+		t.Fatalf("spent %v, expected 0.6", spent)
+	}
+
+	// Second request exceeds the budget and is refused before any row is sent
+	for _, export := range []func() error{
+		func() error {
+			recorder := httptest.NewRecorder()
+			_, err := ExportDataOnServer(ctx, recorder, api)
+			if recorder.Body.Len() > 0 {
+				t.Errorf("body is written: %q", recorder.Body.String())
+			}
+			return err
+		},
+		func() error {
+			_, err := AggregateDataOnServer(ctx, httptest.NewRecorder(), api)
+			return err
+		},
+	} {
+		if err := export(); err == nil || !strings.Contains(err.Error(), "Privacy budget exhausted") {
+			t.Errorf("over budget request: %v", err)
+		}
+	}
+	if spent := ledger.ledger["1/alice"]; spent != 0.6 {
+		t.Errorf("spent %v after rejection, expected 0.6", spent)
+	}
+
+	// Budget of other consumer is not spent
+	if _, err := ExportDataOnServer(WithPrivacyConsumer(context.Background(), "bob"), httptest.NewRecorder(), api); err != nil && strings.Contains(err.Error(), "Privacy budget exhausted") {
+		t.Errorf("other consumer: %v", err)
+	}
+}
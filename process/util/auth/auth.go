@@ -62,6 +62,57 @@ func ExtractAccessTokenOnLambda(ctx context.Context, req events.APIGatewayProxyR
 //	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
 //	token (string): access token
 func AuthenticateAccess(ctx context.Context, tracking bool, opaUrl string, token string) error {
+	// Request decision
+	data, err := requestDecision(ctx, tracking, opaUrl, token)
+	if err != nil {
+		return err
+	} else if value, ok := data["allow"]; ok {
+		// Verify authentication
+		if value == "true" {
+			return nil
+		} else {
+			return errors.New("Unauthentication\r\n")
+		}
+	}
+	return errors.New("Authentication process error\r\n")
+}
+
+// 재식별(re-identification)과 같은 특수 권한이 필요한 접근을 인증하는 함수입니다. OPA server의 응답에 "allow"와 "privileged"가 모두 "true"인 경우에만 접근을 허용합니다.
+//	# Parameters
+//	traking (bool): process tracking status (using AWS X-Ray / need AWS X-Ray configuration)
+//	opaUrl (string): OPA URL [format: <host>:<port>/<path>]
+//	token (string): access token
+func AuthenticatePrivilegedAccess(ctx context.Context, tracking bool, opaUrl string, token string) error {
+	// Request decision
+	data, err := requestDecision(ctx, tracking, opaUrl, token)
+	if err != nil {
+		return err
+	}
+	// Verify authentication
+	allow, ok := data["allow"]
+	if !ok {
+		return errors.New("Authentication process error\r\n")
+	} else if allow != "true" {
+		return errors.New("Unauthentication\r\n")
+	}
+	// Verify privilege
+	if value, ok := data["privileged"]; ok && value == "true" {
+		return nil
+	} else {
+		return errors.New("Unauthorized (privileged access required)\r\n")
+	}
+}
+
+/*
+ * [Private function] Request access decision to OPA server
+ * <IN> ctx (context.Context): context
+ * <IN> tracking (bool): process tracking status
+ * <IN> opaUrl (string): OPA URL
+ * <IN> token (string): access token
+ * <OUT> (map[string]string): decision data
+ * <OUT> (error): error object (contain nil)
+ */
+func requestDecision(ctx context.Context, tracking bool, opaUrl string, token string) (map[string]string, error) {
 	var request *http.Request
 	var err error
 	// Create request object (to OPA server)
@@ -74,7 +125,7 @@ func AuthenticateAccess(ctx context.Context, tracking bool, opaUrl string, token
 	request.Header.Add("Connection", "close")
 	// Catch error
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create authorization attribute value
@@ -94,7 +145,7 @@ func AuthenticateAccess(ctx context.Context, tracking bool, opaUrl string, token
 	// Execute request
 	response, err := client.Do(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer client.CloseIdleConnections()
 	defer response.Body.Close()
@@ -102,19 +153,12 @@ func AuthenticateAccess(ctx context.Context, tracking bool, opaUrl string, token
 	// Read body data
 	result, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Transform to map
 	var data map[string]string
 	if err := json.Unmarshal(result, &data); err != nil {
-		return err
-	} else if value, ok := data["allow"]; ok {
-		// Verify authentication
-		if value == "true" {
-			return nil
-		} else {
-			return errors.New("Unauthentication\r\n")
-		}
+		return nil, err
 	}
-	return errors.New("Authentication process error\r\n")
+	return data, nil
 }
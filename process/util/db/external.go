@@ -2,10 +2,14 @@
 package db
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/gob"
 	"errors"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -31,6 +35,11 @@ import (
 	"github.com/tovdata/privacydam-go/process/util/kAno"
 )
 
+const (
+	// default target k value for k-anonymity evaluation
+	DEFAULT_K_VALUE = 2
+)
+
 // 외부 데이터베이스와의 Connection을 테스트하는 함수입니다.
 //	# Parameters
 //	driverName (string): database driver name (ex. mysql, hdb ...)
@@ -123,10 +132,11 @@ func Ex_changeData(ctx context.Context, sourceId string, querySyntax string, par
 //	querySyntax (string): syntax to query
 //	params ([]interface): API parameter values
 //	didOptions (map[string]model.AnoParamOption): de-identification option by column
+//	evalOption (model.EvaluationOption): k-anonymity evaluation option
 //
 //	# Response
 //	(model.Evaluation): K-anonymity evaluation result
-func Ex_exportData(ctx context.Context, res http.ResponseWriter, routineCount int64, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption) (model.Evaluation, error) {
+func Ex_exportData(ctx context.Context, res http.ResponseWriter, routineCount int64, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption, evalOption model.EvaluationOption) (model.Evaluation, error) {
 	// Get tracking status
 	tracking := util.GetTrackingStatus("processing")
 
@@ -208,10 +218,19 @@ func Ex_exportData(ctx context.Context, res http.ResponseWriter, routineCount in
 		go processDeIdentification(subCtx, tracking, didOptions, columns, tDataQueue, aDataQueue, quitAnony)
 	}
 
-	// Check K-Ano evaluation condition
-	isEval := checkAnoEvaluationCondition(didOptions)
-	// Write data
-	go writeExportedData(subCtx, tracking, res, apiName, columns, isEval, aDataQueue, quitProce)
+	// Create k-anonymity tester (by quasi-identifiers)
+	evaluater := createAnoTester(didOptions, evalOption, columns)
+	// Write data (enforcement mode: write after k-anonymity evaluation)
+	var enforceErr error
+	if evaluater != nil && isEnforcementMode(evalOption.Mode) {
+		go func() {
+			var evaluation model.Evaluation
+			evaluation, enforceErr = writeEnforcedData(subCtx, tracking, res, apiName, columns, evaluater, evalOption, aDataQueue)
+			quitProce <- evaluation
+		}()
+	} else {
+		go writeExportedData(subCtx, tracking, res, apiName, columns, evaluater, aDataQueue, quitProce)
+	}
 
 	// Exit logic
 	completedTrans := uint64(0)
@@ -248,7 +267,7 @@ func Ex_exportData(ctx context.Context, res http.ResponseWriter, routineCount in
 			if tracking {
 				subSegment.Close(nil)
 			}
-			return evaluation, nil
+			return evaluation, enforceErr
 		}
 	}
 }
@@ -262,10 +281,11 @@ func Ex_exportData(ctx context.Context, res http.ResponseWriter, routineCount in
 //	querySyntax (string): syntax to query
 //	params ([]interface): API parameter values
 //	didOptions (map[string]model.AnoParamOption): de-identification option by column
+//	evalOption (model.EvaluationOption): k-anonymity evaluation option
 //
 //	# Response
 //	(model.Evaluation): K-anonymity evaluation result
-func Ex_exportDataOnLambda(ctx context.Context, res *events.APIGatewayProxyResponse, routineCount int64, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption) (model.Evaluation, error) {
+func Ex_exportDataOnLambda(ctx context.Context, res *events.APIGatewayProxyResponse, routineCount int64, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption, evalOption model.EvaluationOption) (model.Evaluation, error) {
 	// Get tracking status
 	tracking := util.GetTrackingStatus("processing")
 
@@ -344,10 +364,19 @@ func Ex_exportDataOnLambda(ctx context.Context, res *events.APIGatewayProxyRespo
 		go processDeIdentification(subCtx, tracking, didOptions, columns, tDataQueue, aDataQueue, quitAnony)
 	}
 
-	// Check K-Ano evaluation condition
-	isEval := checkAnoEvaluationCondition(didOptions)
-	// Write data
-	go writeExportedDataOnLambda(subCtx, tracking, res, apiName, columns, isEval, aDataQueue, quitProce)
+	// Create k-anonymity tester (by quasi-identifiers)
+	evaluater := createAnoTester(didOptions, evalOption, columns)
+	// Write data (enforcement mode: write after k-anonymity evaluation)
+	var enforceErr error
+	if evaluater != nil && isEnforcementMode(evalOption.Mode) {
+		go func() {
+			var evaluation model.Evaluation
+			evaluation, enforceErr = writeEnforcedDataOnLambda(subCtx, tracking, res, apiName, columns, evaluater, evalOption, aDataQueue)
+			quitProce <- evaluation
+		}()
+	} else {
+		go writeExportedDataOnLambda(subCtx, tracking, res, apiName, columns, evaluater, aDataQueue, quitProce)
+	}
 
 	// Exit logic
 	completedTrans := uint64(0)
@@ -384,32 +413,226 @@ func Ex_exportDataOnLambda(ctx context.Context, res *events.APIGatewayProxyRespo
 			if tracking {
 				subSegment.Close(nil)
 			}
-			return evaluation, nil
+			return evaluation, enforceErr
 		}
 	}
 }
 
-func checkAnoEvaluationCondition(didOptions map[string]model.AnoParamOption) bool {
-	// Get options key count
-	total := len(didOptions)
+// 집계 데이터 반출 처리를 수행하는 함수입니다. GROUP BY 쿼리의 결과 중 aggregate 컬럼의 값이 임계값(threshold) 미만인 셀(행)은 제외되며, 설정에 따라 COUNT/SUM 값에 노이즈가 추가됩니다. (For echo framework)
+//	# Parameters
+//	res (http.ResponseWriter): writer for response
+//	apiName (string): API alias
+//	sourceId (string): source uuid by generated database
+//	querySyntax (string): syntax to query (GROUP BY)
+//	params ([]interface): API parameter values
+//	didOptions (map[string]model.AnoParamOption): de-identification option by column
+//
+//	# Response
+//	(model.Evaluation): evaluation result (k-anonymity is not evaluated)
+func Ex_aggregateData(ctx context.Context, res http.ResponseWriter, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption) (model.Evaluation, error) {
+	// Get tracking status
+	tracking := util.GetTrackingStatus("processing")
+
+	// [For debug] Set the subsegment
+	var subCtx context.Context = ctx
+	var subSegment *xray.Segment
+	if tracking {
+		subCtx, subSegment = xray.BeginSubsegment(ctx, "Process aggregation")
+		defer subSegment.Close(nil)
+	}
+
+	// Aggregate
+	columns, aDataQueue, err := processAggregation(subCtx, sourceId, querySyntax, params, didOptions)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	// Write data
+	quitProce := make(chan model.Evaluation, 1)
+	writeExportedData(subCtx, tracking, res, apiName, columns, nil, aDataQueue, quitProce)
+	return <-quitProce, nil
+}
+
+// 집계 데이터 반출 처리를 수행하는 함수입니다. GROUP BY 쿼리의 결과 중 aggregate 컬럼의 값이 임계값(threshold) 미만인 셀(행)은 제외되며, 설정에 따라 COUNT/SUM 값에 노이즈가 추가됩니다. (For aws lambda)
+//	# Parameters
+//	res (*events.APIGatewayProxyResponse): writer for response (AWS API Gateway proxy response)
+//	apiName (string): API alias
+//	sourceId (string): source uuid by generated database
+//	querySyntax (string): syntax to query (GROUP BY)
+//	params ([]interface): API parameter values
+//	didOptions (map[string]model.AnoParamOption): de-identification option by column
+//
+//	# Response
+//	(model.Evaluation): evaluation result (k-anonymity is not evaluated)
+func Ex_aggregateDataOnLambda(ctx context.Context, res *events.APIGatewayProxyResponse, apiName string, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption) (model.Evaluation, error) {
+	// Get tracking status
+	tracking := util.GetTrackingStatus("processing")
+
+	// [For debug] Set the subsegment
+	var subCtx context.Context = ctx
+	var subSegment *xray.Segment
+	if tracking {
+		subCtx, subSegment = xray.BeginSubsegment(ctx, "Process aggregation")
+		defer subSegment.Close(nil)
+	}
+
+	// Aggregate
+	columns, aDataQueue, err := processAggregation(subCtx, sourceId, querySyntax, params, didOptions)
+	if err != nil {
+		return model.Evaluation{}, err
+	}
+	// Write data
+	quitProce := make(chan model.Evaluation, 1)
+	writeExportedDataOnLambda(subCtx, tracking, res, apiName, columns, nil, aDataQueue, quitProce)
+	return <-quitProce, nil
+}
+
+func processAggregation(ctx context.Context, sourceId string, querySyntax string, params []interface{}, didOptions map[string]model.AnoParamOption) ([]string, chan []string, error) {
+	// Get database object
+	dbInfo, err := coreDB.GetDatabase("external", sourceId)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if total == 0 {
-		return false
+	// Execute query
+	var rows *sqlx.Rows
+	if dbInfo.Tracking {
+		rows, err = dbInfo.Instance.QueryxContext(ctx, querySyntax, params...)
 	} else {
-		count := 0
-		for _, option := range didOptions {
-			if option.Level > 0 {
-				break
+		rows, err = dbInfo.Instance.Queryx(querySyntax, params...)
+	}
+	// Catch error
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Build processing functions and suppression thresholds (by aggregate column)
+	seed := new(did.RecordSeed)
+	funcList := buildDeIdentificationFuncs(ctx, didOptions, columns, seed)
+	thresholds := make(map[int]float64)
+	for i, key := range columns {
+		option, exists := didOptions[key]
+		if !exists {
+			continue
+		}
+		for _, step := range did.FlattenOption(option) {
+			if step.Method == "aggregate" && step.Options.Threshold != "" {
+				threshold, err := strconv.ParseFloat(step.Options.Threshold, 64)
+				if err != nil {
+					return nil, nil, errors.New("Invalid threshold parameter\r\n")
+				}
+				thresholds[i] = threshold
 			}
-			count++
 		}
+	}
 
-		if total == count {
-			return false
-		} else {
+	// Extract and process query result (aggregated result is small enough to keep in memory)
+	result := make([][]string, 0)
+	for rows.Next() {
+		allocated := make(map[string]interface{})
+		if err := rows.MapScan(allocated); err != nil {
+			return nil, nil, err
+		}
+
+		seed.Next()
+		output := make([]string, len(columns))
+		for i, key := range columns {
+			output[i] = funcList[i](transformToString(reflect.ValueOf(allocated[key]).Kind().String(), allocated[key]))
+		}
+		// Suppress the small cell (after adding noise, to keep differential privacy)
+		if !isSuppressedCell(output, thresholds) {
+			result = append(result, output)
+		}
+	}
+	// Catch error
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Create data queue
+	aDataQueue := make(chan []string, len(result))
+	for _, row := range result {
+		aDataQueue <- row
+	}
+	close(aDataQueue)
+	return columns, aDataQueue, nil
+}
+
+func isSuppressedCell(row []string, thresholds map[int]float64) bool {
+	for index, threshold := range thresholds {
+		value, err := strconv.ParseFloat(row[index], 64)
+		if err != nil || value < threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// createAnoTester creates a k-anonymity tester evaluating quasi-identifiers (level > 0 or qi flag) only (nil: no quasi-identifier)
+func createAnoTester(didOptions map[string]model.AnoParamOption, evalOption model.EvaluationOption, columns []string) *kAno.AnoTester {
+	// Select quasi-identifiers
+	evalFields := make([]bool, len(columns))
+	isEval := false
+	for i, column := range columns {
+		if option, exists := didOptions[column]; exists && isQuasiIdentifier(option) {
+			evalFields[i] = true
+			isEval = true
+		}
+	}
+	if !isEval {
+		return nil
+	}
+
+	// Set target k value
+	kValue := evalOption.K
+	if kValue <= 0 {
+		kValue = DEFAULT_K_VALUE
+	}
+	evaluater := new(kAno.AnoTester)
+	evaluater.New(len(columns), kValue)
+	evaluater.SetEvalFields(evalFields)
+	evaluater.SetSamplingFraction(evalOption.SamplingFraction)
+	// Set sensitive column (l-diversity, t-closeness)
+	for i, column := range columns {
+		if evalOption.Sensitive != "" && column == evalOption.Sensitive {
+			evaluater.SetSensitiveField(i, evalOption.L, evalOption.T, evalOption.Distance == "ordered", evalOption.Diversity == "entropy")
+		}
+	}
+	return evaluater
+}
+
+// isQuasiIdentifier checks whether a column option (or any step of its chain) marks the column as quasi-identifier
+func isQuasiIdentifier(option model.AnoParamOption) bool {
+	if option.Qi {
+		return true
+	}
+	for _, step := range did.FlattenOption(option) {
+		if step.Level > 0 || step.Qi {
 			return true
 		}
 	}
+	return false
+}
+
+// evaluateSensitive evaluates l-diversity and t-closeness of sensitive column (nil: no sensitive column)
+func evaluateSensitive(evaluater *kAno.AnoTester, header []string, suppression string) *model.SensitiveEvaluation {
+	index := evaluater.GetSensitiveField()
+	if index < 0 {
+		return nil
+	}
+	result, distinct, entropy, closeness := evaluater.EvalSensitive(suppression)
+	return &model.SensitiveEvaluation{
+		Column:    header[index],
+		Result:    strconv.FormatBool(result),
+		Distinct:  int64(distinct),
+		Entropy:   entropy,
+		Closeness: closeness,
+	}
 }
 
 func executeExportQuery(ctx context.Context, tracking bool, rows *sqlx.Rows, iDataQueue chan<- map[string]interface{}, quitQuery chan<- bool) {
@@ -472,40 +695,13 @@ func processDeIdentification(ctx context.Context, tracking bool, options map[str
 		defer subSegment.Close(nil)
 	}
 
-	// build processing functions
-	funcList := make([](func(string) string), len(columns))
-	passAsIs := func(inString string) string {
-		return inString
-	}
-	dropAll := func(inString string) string {
-		return ""
-	}
-
-	for i, key := range columns {
-		if option, exists := options[key]; exists == true {
-			switch option.Method {
-			case "encryption":
-				funcList[i] = did.BuildEncryptingFunc(option.Options)
-			case "rounding":
-				funcList[i] = did.BuildRoundingFunc(option.Options)
-			case "data_range":
-				funcList[i] = did.BuildRangingFunc(option.Options)
-			case "blank_impute":
-				funcList[i] = did.BuildMaskingFunc(option.Options)
-			case "pii_reduction":
-				funcList[i] = did.BuildMaskingFunc(option.Options)
-			case "non":
-				funcList[i] = passAsIs
-			default:
-				funcList[i] = dropAll
-			}
-		} else {
-			funcList[i] = passAsIs
-		}
-	}
+	// build processing functions (random value of record is shared by columns)
+	seed := new(did.RecordSeed)
+	funcList := buildDeIdentificationFuncs(ctx, options, columns, seed)
 
 	cnt := 0
 	for v, ok := <-tDataQueue; ok; v, ok = <-tDataQueue {
+		seed.Next()
 		output := make([]string, len(columns))
 		for i, value := range v {
 			output[i] = funcList[i](value)
@@ -518,7 +714,59 @@ func processDeIdentification(ctx context.Context, tracking bool, options map[str
 	quitAnony <- true
 }
 
-func writeExportedData(ctx context.Context, tracking bool, res http.ResponseWriter, name string, header []string, isEval bool, aDataQueue <-chan []string, quitProce chan<- model.Evaluation) {
+func buildDeIdentificationFuncs(ctx context.Context, options map[string]model.AnoParamOption, columns []string, seed *did.RecordSeed) [](func(string) string) {
+	// Resources used by built-in methods
+	env := &did.BuildEnvironment{
+		Vault: &internalTokenVault{ctx: ctx},
+		Taxonomy: func(name string) (did.Taxonomy, error) {
+			return In_getTaxonomy(ctx, name)
+		},
+		Seed: seed,
+	}
+
+	funcList := make([](func(string) string), len(columns))
+	for i, key := range columns {
+		if option, exists := options[key]; exists == true {
+			funcList[i] = buildDeIdentificationFunc(option, env)
+		} else {
+			funcList[i] = passAsIs
+		}
+	}
+	return funcList
+}
+
+func buildDeIdentificationFunc(option model.AnoParamOption, env *did.BuildEnvironment) func(string) string {
+	// Chained methods (applied in order)
+	if len(option.Chain) > 0 {
+		steps := make([](func(string) string), len(option.Chain))
+		for i, step := range option.Chain {
+			steps[i] = buildDeIdentificationFunc(step, env)
+		}
+		return did.ChainFuncs(steps...)
+	}
+
+	// Unknown method
+	if !did.IsSupportedMethod(option.Method) {
+		return dropAll
+	}
+	fn, err := did.BuildMethodFunc(option, env)
+	if err != nil {
+		return func(inString string) string {
+			return err.Error()
+		}
+	}
+	return fn
+}
+
+func passAsIs(inString string) string {
+	return inString
+}
+
+func dropAll(inString string) string {
+	return ""
+}
+
+func writeExportedData(ctx context.Context, tracking bool, res http.ResponseWriter, name string, header []string, evaluater *kAno.AnoTester, aDataQueue <-chan []string, quitProce chan<- model.Evaluation) {
 	// Set the subsegment
 	if tracking {
 		_, subSegment := xray.BeginSubsegment(ctx, "Write data in response body")
@@ -535,20 +783,13 @@ func writeExportedData(ctx context.Context, tracking bool, res http.ResponseWrit
 	res.Header().Set("Content-Disposition", "attachment;filename="+filename)
 	res.Header().Set("Content-Type", "application/octet-stream")
 
-	// Create k-anonymity tester
-	var evaluater *kAno.AnoTester
-	if isEval {
-		evaluater = new(kAno.AnoTester)
-		evaluater.New(len(header), 2)
-	}
-
 	// Transform header data to csv format
 	buffer := transformToCsvFormat(header)
 	res.Write(buffer.Bytes())
 	// Export process
 	for row, ok := <-aDataQueue; ok; row, ok = <-aDataQueue {
 		// Add data to evaluate k-anonymity
-		if isEval {
+		if evaluater != nil {
 			evaluater.AddStrings(row)
 		}
 		// Transform exported data and write data
@@ -563,10 +804,13 @@ func writeExportedData(ctx context.Context, tracking bool, res http.ResponseWrit
 		Result:  "none",
 		Value:   int64(0),
 	}
-	if isEval {
+	if evaluater != nil {
 		evalResult, actValue := evaluater.Eval()
 		evaluation.Result = strconv.FormatBool(evalResult)
 		evaluation.Value = int64(actValue)
+		evaluation.Sensitive = evaluateSensitive(evaluater, header, "")
+		risk := evaluater.EvalRisk("")
+		evaluation.Risk = &risk
 	}
 
 	// Exit
@@ -574,20 +818,13 @@ func writeExportedData(ctx context.Context, tracking bool, res http.ResponseWrit
 	evaluater = nil
 }
 
-func writeExportedDataOnLambda(ctx context.Context, tracking bool, res *events.APIGatewayProxyResponse, name string, header []string, isEval bool, aDataQueue <-chan []string, quitProce chan<- model.Evaluation) {
+func writeExportedDataOnLambda(ctx context.Context, tracking bool, res *events.APIGatewayProxyResponse, name string, header []string, evaluater *kAno.AnoTester, aDataQueue <-chan []string, quitProce chan<- model.Evaluation) {
 	// Set the subsegment
 	if tracking {
 		_, subSegment := xray.BeginSubsegment(ctx, "Write data in response body")
 		defer subSegment.Close(nil)
 	}
 
-	// Create k-anonymity tester
-	var evaluater *kAno.AnoTester
-	if isEval {
-		evaluater = new(kAno.AnoTester)
-		evaluater.New(len(header), 2)
-	}
-
 	// Set body
 	var body bytes.Buffer
 
@@ -598,7 +835,7 @@ func writeExportedDataOnLambda(ctx context.Context, tracking bool, res *events.A
 	// Export process
 	for row, ok := <-aDataQueue; ok; row, ok = <-aDataQueue {
 		// Add data to evaluate k-anonymity
-		if isEval {
+		if evaluater != nil {
 			evaluater.AddStrings(row)
 		}
 		// Transform exported data and write data
@@ -617,10 +854,13 @@ func writeExportedDataOnLambda(ctx context.Context, tracking bool, res *events.A
 		Result:  "none",
 		Value:   int64(0),
 	}
-	if isEval {
+	if evaluater != nil {
 		evalResult, actValue := evaluater.Eval()
 		evaluation.Result = strconv.FormatBool(evalResult)
 		evaluation.Value = int64(actValue)
+		evaluation.Sensitive = evaluateSensitive(evaluater, header, "")
+		risk := evaluater.EvalRisk("")
+		evaluation.Risk = &risk
 	}
 
 	// Exit
@@ -628,6 +868,201 @@ func writeExportedDataOnLambda(ctx context.Context, tracking bool, res *events.A
 	evaluater = nil
 }
 
+func isEnforcementMode(mode string) bool {
+	return mode == "reject" || mode == "suppress"
+}
+
+func writeEnforcedData(ctx context.Context, tracking bool, res http.ResponseWriter, name string, header []string, evaluater *kAno.AnoTester, evalOption model.EvaluationOption, aDataQueue <-chan []string) (model.Evaluation, error) {
+	// Set the subsegment
+	if tracking {
+		_, subSegment := xray.BeginSubsegment(ctx, "Write enforced data in response body")
+		defer subSegment.Close(nil)
+	}
+
+	return enforceAnoEvaluation(name, header, evaluater, evalOption, aDataQueue, func() {
+		// Set a file name
+		filename := name + "_export.csv"
+		// Set response header
+		res.Header().Set("Connection", "Keep-Alive")
+		res.Header().Set("Transfer-Encoding", "chunked")
+		res.Header().Set("X-Content-Type-Options", "nosniff")
+		// Set stream file in response header
+		res.Header().Set("Content-Disposition", "attachment;filename="+filename)
+		res.Header().Set("Content-Type", "application/octet-stream")
+	}, func(row []string) {
+		buffer := transformToCsvFormat(row)
+		res.Write(buffer.Bytes())
+	})
+}
+
+func writeEnforcedDataOnLambda(ctx context.Context, tracking bool, res *events.APIGatewayProxyResponse, name string, header []string, evaluater *kAno.AnoTester, evalOption model.EvaluationOption, aDataQueue <-chan []string) (model.Evaluation, error) {
+	// Set the subsegment
+	if tracking {
+		_, subSegment := xray.BeginSubsegment(ctx, "Write enforced data in response body")
+		defer subSegment.Close(nil)
+	}
+
+	// Set body
+	var body bytes.Buffer
+	evaluation, err := enforceAnoEvaluation(name, header, evaluater, evalOption, aDataQueue, func() {}, func(row []string) {
+		buffer := transformToCsvFormat(row)
+		body.Write(buffer.Bytes())
+	})
+	// Write response body
+	if err == nil {
+		res.Body = body.String()
+	}
+	return evaluation, err
+}
+
+// enforceAnoEvaluation spools de-identified rows into a temporary file and writes them only after k-anonymity evaluation
+// (reject: nothing is written if the evaluation fails, suppress: rows of equivalence classes smaller than k are dropped or blanked)
+func enforceAnoEvaluation(name string, header []string, evaluater *kAno.AnoTester, evalOption model.EvaluationOption, aDataQueue <-chan []string, prepare func(), write func([]string)) (model.Evaluation, error) {
+	evaluation := model.Evaluation{
+		ApiName: name,
+		Result:  "none",
+		Value:   int64(0),
+	}
+
+	// Spool data
+	file, err := spoolExportedData(evaluater, aDataQueue)
+	if file != nil {
+		defer os.Remove(file.Name())
+		defer file.Close()
+	}
+	if err != nil {
+		return evaluation, err
+	}
+
+	// Evaluate k-anonymity
+	evalResult, actValue := evaluater.Eval()
+	evaluation.Result = strconv.FormatBool(evalResult)
+	evaluation.Value = int64(actValue)
+	evaluation.Total = int64(evaluater.GetRowCount())
+	// Select suppression of released data (blanked rows make an equivalence class, so they are dropped if the class is still smaller than k)
+	suppression := ""
+	if !evalResult && evalOption.Mode == "suppress" {
+		suppression = "drop"
+		if evalOption.Suppression == "blank" && evaluater.GetSuppressionCount() >= evaluater.GetTargetKValue() {
+			suppression = "blank"
+		}
+	}
+	evaluation.Sensitive = evaluateSensitive(evaluater, header, suppression)
+	// Evaluate re-identification risk (of released data)
+	risk := evaluater.EvalRisk(suppression)
+	evaluation.Risk = &risk
+	if !evalResult && evalOption.Mode == "reject" {
+		return evaluation, errors.New("K-anonymity requirement is not satisfied (k: " + strconv.Itoa(actValue) + ")\r\n")
+	}
+
+	// Check suppression rate
+	if suppression != "" {
+		suppressed := int64(evaluater.GetSuppressionCount())
+		if suppression == "drop" && suppressed >= evaluation.Total {
+			evaluation.Result = "false"
+			return evaluation, errors.New("All rows are suppressed (total: " + strconv.FormatInt(evaluation.Total, 10) + ")\r\n")
+		}
+		if evalOption.MaxSuppressionRate > 0 && float64(suppressed) > evalOption.MaxSuppressionRate*float64(evaluation.Total) {
+			return evaluation, errors.New("Suppression rate exceeded (suppressed: " + strconv.FormatInt(suppressed, 10) + ", total: " + strconv.FormatInt(evaluation.Total, 10) + ")\r\n")
+		}
+	}
+
+	// Write spooled data
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return evaluation, err
+	}
+	prepare()
+	write(header)
+	decoder := gob.NewDecoder(bufio.NewReader(file))
+	minValue := 0
+	for {
+		var row []string
+		if err := decoder.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return evaluation, err
+		} else if len(row) != len(header) {
+			return evaluation, errors.New("Spooled row is corrupted\r\n")
+		}
+		// Suppress rows of small equivalence class
+		if suppression != "" {
+			frequency := evaluater.Frequency(row)
+			if frequency < evaluater.GetTargetKValue() {
+				evaluation.Suppressed++
+				if suppression == "blank" {
+					for i := range row {
+						if evaluater.IsEvalField(i) {
+							row[i] = ""
+						}
+					}
+					write(row)
+				}
+				continue
+			}
+			if minValue == 0 || frequency < minValue {
+				minValue = frequency
+			}
+		}
+		write(row)
+	}
+
+	// Result after suppression (blanked rows make an equivalence class)
+	if suppression != "" {
+		if suppression == "blank" && evaluation.Suppressed > 0 && (minValue == 0 || evaluation.Suppressed < int64(minValue)) {
+			minValue = int(evaluation.Suppressed)
+		}
+		evaluation.Result = strconv.FormatBool(minValue >= evaluater.GetTargetKValue())
+		evaluation.Value = int64(minValue)
+	}
+	return evaluation, nil
+}
+
+// spoolExportedData writes rows into a temporary file (bounded by SPOOL_SIZE) and adds them to k-anonymity tester
+// (rows are gob-encoded so that values are read back as they are, including empty fields and CR/LF)
+func spoolExportedData(evaluater *kAno.AnoTester, aDataQueue <-chan []string) (*os.File, error) {
+	// Get spool size from environment various (default: 1GB)
+	spoolSize, err := strconv.ParseInt(os.Getenv("SPOOL_SIZE"), 10, 64)
+	if err != nil {
+		spoolSize = 1 << 30
+	}
+
+	file, err := ioutil.TempFile("", "privacydam_spool_*.gob")
+	if err != nil {
+		// Drain queue (to release go-routines)
+		for range aDataQueue {
+		}
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	counter := &countingWriter{writer: writer}
+	encoder := gob.NewEncoder(counter)
+	for row, ok := <-aDataQueue; ok; row, ok = <-aDataQueue {
+		if err != nil {
+			continue
+		}
+		evaluater.AddStrings(row)
+		if err = encoder.Encode(row); err == nil && counter.count > spoolSize {
+			err = errors.New("Spool size exceeded\r\n")
+		}
+	}
+	if err != nil {
+		return file, err
+	}
+	return file, writer.Flush()
+}
+
+// countingWriter counts written bytes
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
 // func allocateMemoryByScanType(columns []*sql.ColumnType) []interface{} {
 // 	allocated := make([]interface{}, len(columns))
 // 	for i, column := range columns {
@@ -675,8 +1110,12 @@ func transformToString(scanType string, elem interface{}) string {
 		converted = strconv.FormatBool(elem.(bool))
 	case "string":
 		converted = (elem.(string))
-	case "time.time":
-		converted = (elem.(time.Time).Format("2006-01-02T15:04:05"))
+	case "time.time", "struct":
+		if value, ok := elem.(time.Time); ok {
+			converted = value.Format("2006-01-02T15:04:05")
+		} else {
+			converted = "-/-"
+		}
 	case "sql.RawBytes", "slice":
 		converted = string(elem.([]byte))
 	// case "driver.Decimal":
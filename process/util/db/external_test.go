@@ -0,0 +1,169 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	// Model
+	"github.com/tovdata/privacydam-go/core/model"
+)
+
+// enforce runs enforceAnoEvaluation on rows and returns the written rows (including header)
+func enforce(didOptions map[string]model.AnoParamOption, evalOption model.EvaluationOption, header []string, rows [][]string) (model.Evaluation, [][]string, error) {
+	aDataQueue := make(chan []string, len(rows))
+	for _, row := range rows {
+		aDataQueue <- row
+	}
+	close(aDataQueue)
+
+	evaluater := createAnoTester(didOptions, evalOption, header)
+	written := [][]string{}
+	evaluation, err := enforceAnoEvaluation("test", header, evaluater, evalOption, aDataQueue, func() {}, func(row []string) {
+		written = append(written, append([]string(nil), row...))
+	})
+	return evaluation, written, err
+}
+
+func TestSpoolRoundTrip(t *testing.T) {
+	rows := [][]string{{""}, {""}, {"a\r\nb"}, {"a\r\nb"}, {"\r"}, {"\r"}, {"\"quoted\",\n"}, {"\"quoted\",\n"}}
+	evaluation, written, err := enforce(map[string]model.AnoParamOption{"memo": {Qi: true}}, model.EvaluationOption{K: 2, Mode: "reject"}, []string{"memo"}, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evaluation.Result != "true" || evaluation.Total != int64(len(rows)) {
+		t.Errorf("evaluation %+v", evaluation)
+	}
+	if !reflect.DeepEqual(written[1:], rows) {
+		t.Errorf("spooled rows are changed: %q", written[1:])
+	}
+}
+
+func TestSpoolAndReject(t *testing.T) {
+	didOptions := map[string]model.AnoParamOption{"age": {Qi: true}}
+	header := []string{"age", "disease"}
+	for _, test := range []struct {
+		name     string
+		rows     [][]string
+		result   string
+		rejected bool
+	}{
+		{"satisfied", [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}, {"30", "flu"}}, "true", false},
+		{"unique row", [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}}, "false", true},
+		{"all unique", [][]string{{"20", "flu"}, {"30", "cold"}}, "false", true},
+	} {
+		evaluation, written, err := enforce(didOptions, model.EvaluationOption{K: 2, Mode: "reject"}, header, test.rows)
+		if evaluation.Result != test.result {
+			t.Errorf("%s: result %q, expected %q", test.name, evaluation.Result, test.result)
+		}
+		if test.rejected {
+			if err == nil || !strings.Contains(err.Error(), "K-anonymity requirement is not satisfied") {
+				t.Errorf("%s: not rejected (%v)", test.name, err)
+			}
+			if len(written) > 0 {
+				t.Errorf("%s: rows are written before rejection: %q", test.name, written)
+			}
+		} else if err != nil || len(written) != len(test.rows)+1 {
+			t.Errorf("%s: %v, written %q", test.name, err, written)
+		}
+	}
+}
+
+func TestAnoTesterChainedQuasiIdentifier(t *testing.T) {
+	didOptions := map[string]model.AnoParamOption{
+		"address": {Chain: []model.AnoParamOption{{Method: "normalization"}, {Method: "hierarchy", Level: 1}}},
+		"zipcode": {Chain: []model.AnoParamOption{{Method: "normalization"}, {Method: "masking", Qi: true}}},
+		"name":    {Chain: []model.AnoParamOption{{Method: "normalization"}, {Method: "masking"}}},
+	}
+	evaluater := createAnoTester(didOptions, model.EvaluationOption{}, []string{"address", "zipcode", "name"})
+	if evaluater == nil {
+		t.Fatal("chained quasi-identifiers are ignored")
+	}
+	for i, expected := range []bool{true, true, false} {
+		if evaluater.IsEvalField(i) != expected {
+			t.Errorf("column %d: quasi-identifier %v, expected %v", i, !expected, expected)
+		}
+	}
+}
+
+func TestSpoolAndSuppress(t *testing.T) {
+	didOptions := map[string]model.AnoParamOption{"age": {Qi: true}}
+	header := []string{"age", "disease"}
+	for _, test := range []struct {
+		name       string
+		evalOption model.EvaluationOption
+		rows       [][]string
+		written    [][]string
+		suppressed int64
+		value      int64
+		failed     string
+	}{
+		{
+			name:       "drop",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress"},
+			rows:       [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}},
+			written:    [][]string{header, {"20", "flu"}, {"20", "cold"}},
+			suppressed: 1,
+			value:      2,
+		},
+		{
+			name:       "blank (merged into a class of k)",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress", Suppression: "blank"},
+			rows:       [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}, {"40", "cold"}},
+			written:    [][]string{header, {"20", "flu"}, {"20", "cold"}, {"", "flu"}, {"", "cold"}},
+			suppressed: 2,
+			value:      2,
+		},
+		{
+			name:       "blank (merged class smaller than k is dropped)",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress", Suppression: "blank"},
+			rows:       [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}},
+			written:    [][]string{header, {"20", "flu"}, {"20", "cold"}},
+			suppressed: 1,
+			value:      2,
+		},
+		{
+			name:       "blank (all rows)",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress", Suppression: "blank"},
+			rows:       [][]string{{"20", "flu"}, {"30", "cold"}},
+			written:    [][]string{header, {"", "flu"}, {"", "cold"}},
+			suppressed: 2,
+			value:      2,
+		},
+		{
+			name:       "all rows dropped",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress"},
+			rows:       [][]string{{"20", "flu"}, {"30", "cold"}},
+			written:    [][]string{},
+			failed:     "All rows are suppressed",
+		},
+		{
+			name:       "suppression rate exceeded",
+			evalOption: model.EvaluationOption{K: 2, Mode: "suppress", MaxSuppressionRate: 0.2},
+			rows:       [][]string{{"20", "flu"}, {"20", "cold"}, {"30", "flu"}},
+			written:    [][]string{},
+			failed:     "Suppression rate exceeded",
+		},
+	} {
+		evaluation, written, err := enforce(didOptions, test.evalOption, header, test.rows)
+		if test.failed != "" {
+			if err == nil || !strings.Contains(err.Error(), test.failed) {
+				t.Errorf("%s: %v, expected %q", test.name, err, test.failed)
+			}
+			if evaluation.Result != "false" || len(written) > 0 {
+				t.Errorf("%s: result %q, written %q", test.name, evaluation.Result, written)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(written, test.written) {
+			t.Errorf("%s: written %q, expected %q", test.name, written, test.written)
+		}
+		if evaluation.Result != "true" || evaluation.Suppressed != test.suppressed || evaluation.Value != test.value {
+			t.Errorf("%s: evaluation %+v", test.name, evaluation)
+		}
+	}
+}
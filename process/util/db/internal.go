@@ -3,7 +3,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	// ORM
 	"github.com/jmoiron/sqlx"
@@ -16,8 +21,28 @@ import (
 
 	// Core (database pool)
 	coreDB "github.com/tovdata/privacydam-go/core/db"
+
+	// Util
+	"github.com/tovdata/privacydam-go/process/util/did"
+)
+
+const (
+	TAXONOMY_CACHE_TTL = time.Minute
+
+	DEFAULT_PRIVACY_BUDGET        = 1.0
+	DEFAULT_PRIVACY_BUDGET_WINDOW = 24
 )
 
+var (
+	taxonomyCache = make(map[string]cachedTaxonomy)
+	taxonomyMutex = &sync.Mutex{}
+)
+
+type cachedTaxonomy struct {
+	taxonomy did.Taxonomy
+	loadedAt time.Time
+}
+
 // 내부 데이터베이스로부터 API의 정보를 가져오는 함수입니다.
 //	# Parameters
 //	param (string): value to find API (= API alias)
@@ -35,7 +60,7 @@ func In_findApiFromDB(ctx context.Context, param string) (model.Api, error) {
 
 	// Execute query (get a api information)
 	var rows *sqlx.Rows
-	querySyntax := `SELECT api_id, source_id, api_name, api_alias, api_type, syntax "queryContent.syntax", reg_date, exp_date, status FROM api WHERE api_alias=?`
+	querySyntax := `SELECT a.api_id, a.source_id, a.api_name, a.api_alias, a.api_type, a.syntax "queryContent.syntax", a.reg_date, a.exp_date, a.status, d.options "queryContent.rawDidOptions", d.eval_option "queryContent.rawEvalOption" FROM api AS a LEFT JOIN did_option AS d ON a.api_id=d.api_id WHERE a.api_alias=?`
 	if dbInfo.Tracking {
 		rows, err = dbInfo.Instance.QueryxContext(ctx, querySyntax, param)
 	} else {
@@ -59,6 +84,12 @@ func In_findApiFromDB(ctx context.Context, param string) (model.Api, error) {
 	} else if info.Uuid == "" {
 		return info, errors.New("Not found API (Please check if the API alias is correct)\r\n")
 	}
+	// Transform k-anonymity evaluation option
+	if info.QueryContent.RawEvalOption.Valid && info.QueryContent.RawEvalOption.String != "" {
+		if err := json.Unmarshal([]byte(info.QueryContent.RawEvalOption.String), &info.QueryContent.EvalOption); err != nil {
+			return info, errors.New("Invalid k-anonymity evaluation option (" + err.Error() + ")\r\n")
+		}
+	}
 
 	// Allocate memory to store parameters
 	info.QueryContent.ParamsKey = make([]string, 0)
@@ -129,6 +160,230 @@ func In_findApi(param string) (model.Api, error) {
 	}
 }
 
+// 내부 데이터베이스의 가명 토큰 저장소(vault)에서 digest에 매핑된 토큰을 가져오는 함수입니다. 매핑된 토큰이 없을 경우 후보 토큰(candidate)을 저장하고 반환합니다.
+// 저장소 테이블(pseudonym_vault)은 (vault_name, value_digest)와 (vault_name, token)에 대해 각각 Unique key를 가져야 합니다.
+//	# Parameters
+//	vault (string): vault name
+//	digest (string): digest of original value
+//	candidate (string): random token to store when the mapping does not exist
+//
+//	# Response
+//	(string): token mapped to digest
+func In_tokenize(ctx context.Context, vault string, digest string, candidate string) (string, error) {
+	// Set default return value
+	var token string
+
+	// Get database object
+	dbInfo, err := coreDB.GetDatabase("internal", nil)
+	if err != nil {
+		return token, err
+	}
+
+	// Execute query (store candidate token, ignored when the digest or token already exists)
+	querySyntax := `INSERT IGNORE INTO pseudonym_vault (vault_name, value_digest, token) VALUE (?, ?, ?)`
+	if dbInfo.Tracking {
+		_, err = dbInfo.Instance.ExecContext(ctx, querySyntax, vault, digest, candidate)
+	} else {
+		_, err = dbInfo.Instance.Exec(querySyntax, vault, digest, candidate)
+	}
+	// Catch error
+	if err != nil {
+		return token, err
+	}
+
+	// Execute query (get a mapped token)
+	querySyntax = `SELECT token FROM pseudonym_vault WHERE vault_name=? AND value_digest=?`
+	if dbInfo.Tracking {
+		err = dbInfo.Instance.QueryRowContext(ctx, querySyntax, vault, digest).Scan(&token)
+	} else {
+		err = dbInfo.Instance.QueryRow(querySyntax, vault, digest).Scan(&token)
+	}
+	// Catch error
+	if err == sql.ErrNoRows {
+		// Candidate token is already mapped to another digest
+		return token, did.ErrTokenCollision
+	}
+	return token, err
+}
+
+// 내부 데이터베이스를 이용하는 가명 토큰 저장소 (implements did.TokenVault)
+type internalTokenVault struct {
+	ctx context.Context
+}
+
+func (v *internalTokenVault) Tokenize(vault string, digest string, candidate string) (string, error) {
+	return In_tokenize(v.ctx, vault, digest, candidate)
+}
+
+// 내부 데이터베이스로부터 일반화에 사용할 분류 체계(taxonomy)를 가져오는 함수입니다. 불러온 분류 체계는 일정 시간(TAXONOMY_CACHE_TTL) 동안 메모리 상에 캐싱됩니다.
+//	# Parameters
+//	name (string): taxonomy name
+//
+//	# Response
+//	(did.Taxonomy): loaded taxonomy (node -> parent)
+func In_getTaxonomy(ctx context.Context, name string) (did.Taxonomy, error) {
+	// Lock
+	taxonomyMutex.Lock()
+	// Unlock
+	defer taxonomyMutex.Unlock()
+
+	// Find cached taxonomy
+	if cached, ok := taxonomyCache[name]; ok && time.Since(cached.loadedAt) < TAXONOMY_CACHE_TTL {
+		return cached.taxonomy, nil
+	}
+
+	// Get database object
+	dbInfo, err := coreDB.GetDatabase("internal", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute query (get a list of nodes)
+	var rows *sql.Rows
+	querySyntax := `SELECT node, parent FROM taxonomy WHERE taxonomy_name=?`
+	if dbInfo.Tracking {
+		rows, err = dbInfo.Instance.QueryContext(ctx, querySyntax, name)
+	} else {
+		rows, err = dbInfo.Instance.Query(querySyntax, name)
+	}
+	// Catch error
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Extract query result
+	taxonomy := make(did.Taxonomy)
+	for rows.Next() {
+		var node string
+		var parent sql.NullString
+		if err := rows.Scan(&node, &parent); err != nil {
+			return nil, err
+		}
+		taxonomy[node] = parent.String
+	}
+	// Catch error
+	if err := rows.Err(); err != nil {
+		return nil, err
+	} else if len(taxonomy) == 0 {
+		return nil, errors.New("Not found taxonomy\r\n")
+	}
+
+	// Store cache
+	taxonomyCache[name] = cachedTaxonomy{taxonomy: taxonomy, loadedAt: time.Now()}
+	return taxonomy, nil
+}
+
+// 내부 데이터베이스로부터 소비자(consumer)의 API별 프라이버시 예산 현황을 가져오는 함수입니다.
+// 예산은 privacy_budget 테이블(consumer '*'는 API 기본값)에서, 사용량은 privacy_ledger 테이블에서 조회하며, 설정이 없을 경우 환경 변수(PRIVACY_BUDGET, PRIVACY_BUDGET_WINDOW)의 값을 사용합니다.
+//	# Parameters
+//	apiId (string): API uuid by generated database
+//	consumer (string): consumer identifier
+//
+//	# Response
+//	(model.PrivacyBudget): privacy budget status
+func In_getPrivacyBudget(ctx context.Context, apiId string, consumer string) (model.PrivacyBudget, error) {
+	// Get database object
+	dbInfo, err := coreDB.GetDatabase("internal", nil)
+	if err != nil {
+		return model.PrivacyBudget{}, err
+	}
+	return queryPrivacyBudget(ctx, dbInfo.Tracking, dbInfo.Instance, apiId, consumer, false)
+}
+
+// 소비자(consumer)의 프라이버시 예산에서 epsilon만큼을 차감(ledger 기록)하는 함수입니다. 남은 예산이 부족할 경우 차감하지 않고 에러를 반환합니다.
+//	# Parameters
+//	apiId (string): API uuid by generated database
+//	consumer (string): consumer identifier
+//	epsilon (float64): epsilon to spend
+//
+//	# Response
+//	(model.PrivacyBudget): privacy budget status after spending
+func In_spendPrivacyBudget(ctx context.Context, apiId string, consumer string, epsilon float64) (model.PrivacyBudget, error) {
+	// Get database object
+	dbInfo, err := coreDB.GetDatabase("internal", nil)
+	if err != nil {
+		return model.PrivacyBudget{}, err
+	}
+
+	// Begin transaction
+	tx, err := dbInfo.Instance.Beginx()
+	if err != nil {
+		return model.PrivacyBudget{}, err
+	}
+	defer tx.Rollback()
+
+	// Get privacy budget status (lock ledger rows)
+	budget, err := queryPrivacyBudget(ctx, dbInfo.Tracking, tx, apiId, consumer, true)
+	if err != nil {
+		return budget, err
+	} else if budget.Remaining < epsilon {
+		return budget, errors.New("Privacy budget exhausted\r\n")
+	}
+
+	// Execute query (write ledger)
+	querySyntax := `INSERT INTO privacy_ledger (api_id, consumer, epsilon) VALUE (?, ?, ?)`
+	if dbInfo.Tracking {
+		_, err = tx.ExecContext(ctx, querySyntax, apiId, consumer, epsilon)
+	} else {
+		_, err = tx.Exec(querySyntax, apiId, consumer, epsilon)
+	}
+	// Catch error
+	if err != nil {
+		return budget, err
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return budget, err
+	}
+	budget.Spent += epsilon
+	budget.Remaining -= epsilon
+	return budget, nil
+}
+
+func queryPrivacyBudget(ctx context.Context, tracking bool, queryer sqlx.QueryerContext, apiId string, consumer string, lock bool) (model.PrivacyBudget, error) {
+	// Set default privacy budget
+	budget := model.PrivacyBudget{
+		Consumer:   consumer,
+		Budget:     DEFAULT_PRIVACY_BUDGET,
+		WindowHour: DEFAULT_PRIVACY_BUDGET_WINDOW,
+	}
+	if value, err := strconv.ParseFloat(os.Getenv("PRIVACY_BUDGET"), 64); err == nil {
+		budget.Budget = value
+	}
+	if value, err := strconv.ParseInt(os.Getenv("PRIVACY_BUDGET_WINDOW"), 10, 64); err == nil {
+		budget.WindowHour = value
+	}
+	if !tracking {
+		ctx = context.Background()
+	}
+
+	// Execute query (get a budget configuration, consumer configuration first)
+	var configured []struct {
+		Budget     float64 `db:"budget"`
+		WindowHour int64   `db:"window_hour"`
+	}
+	querySyntax := `SELECT budget, window_hour FROM privacy_budget WHERE api_id=? AND consumer IN (?, '*') ORDER BY consumer='*' LIMIT 1`
+	if err := sqlx.SelectContext(ctx, queryer, &configured, querySyntax, apiId, consumer); err != nil {
+		return budget, err
+	} else if len(configured) > 0 {
+		budget.Budget = configured[0].Budget
+		budget.WindowHour = configured[0].WindowHour
+	}
+
+	// Execute query (get a spent epsilon in window)
+	querySyntax = `SELECT IFNULL(SUM(epsilon), 0) FROM privacy_ledger WHERE api_id=? AND consumer=? AND reg_date >= DATE_SUB(NOW(), INTERVAL ? HOUR)`
+	if lock {
+		querySyntax += ` FOR UPDATE`
+	}
+	if err := sqlx.GetContext(ctx, queryer, &budget.Spent, querySyntax, apiId, consumer, budget.WindowHour); err != nil {
+		return budget, err
+	}
+	budget.Remaining = budget.Budget - budget.Spent
+	return budget, nil
+}
+
 // func In_writeProcessLog(ctx context.Context, accessor model.Accessor, apiId string, apiType string, evaluation model.Evaluation, finalResult string) error {
 // 	// Get database object
 // 	dbInfo, err := coreDB.GetDatabase("internal", nil)
@@ -0,0 +1,110 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// resident registration number prefix (YYMMDD-G)
+var rrnPrefixPattern = regexp.MustCompile(`^(\d{6})-?(\d)`)
+
+// 생년월일 또는 주민등록번호(앞 7자리)로부터 기준일(Reference) 기준의 나이를 계산하는 함수를 생성하는 함수입니다.
+// Algorithm이 "age"인 경우 나이를, "band"(default)인 경우 Unit(default: 10) 단위의 연령대(ex. 30-39)를, "range"인 경우 data_range와 동일한 구간(Lower, Upper, Bin)을 반환합니다.
+func BuildAgingFunc(options model.AnoOption) func(string) string {
+	reference := time.Now()
+	if options.Reference != "" {
+		date, _, err := parseDate(options.Reference)
+		if err != nil {
+			return func(inString string) string {
+				return "reference parameter error"
+			}
+		}
+		reference = date
+	}
+
+	var format func(int) string
+	switch options.Algorithm {
+	case "age":
+		format = strconv.Itoa
+	case "", "band":
+		width := 10
+		if options.Unit != "" {
+			value, err := strconv.ParseInt(options.Unit, 10, 0)
+			if err != nil || value <= 0 {
+				return func(inString string) string {
+					return "unit parameter error"
+				}
+			}
+			width = int(value)
+		}
+		format = func(age int) string {
+			lower := (age / width) * width
+			return fmt.Sprintf("%d-%d", lower, lower+width-1)
+		}
+	case "range":
+		ranging := BuildRangingFunc(options)
+		format = func(age int) string {
+			return ranging(strconv.Itoa(age))
+		}
+	default:
+		return func(inString string) string {
+			return "unknown Aging algorithm"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		birth, err := parseBirthDate(inString)
+		if err != nil {
+			return err.Error()
+		}
+		age := calculateAge(birth, reference)
+		if age < 0 {
+			return "age calculation error"
+		}
+		return format(age)
+	}
+}
+
+// parseBirthDate parses the birth date from date string or resident registration number
+func parseBirthDate(inString string) (time.Time, error) {
+	if date, _, err := parseDate(inString); err == nil {
+		return date, nil
+	}
+
+	matched := rrnPrefixPattern.FindStringSubmatch(inString)
+	if matched == nil {
+		return time.Time{}, errors.New("parseBirthDate error")
+	}
+	// Decide century by gender digit
+	var century string
+	switch matched[2] {
+	case "9", "0":
+		century = "18"
+	case "1", "2", "5", "6":
+		century = "19"
+	case "3", "4", "7", "8":
+		century = "20"
+	}
+	date, err := time.Parse("20060102", century+matched[1])
+	if err != nil {
+		return time.Time{}, errors.New("parseBirthDate error")
+	}
+	return date, nil
+}
+
+func calculateAge(birth time.Time, reference time.Time) int {
+	age := reference.Year() - birth.Year()
+	if reference.Month() < birth.Month() || (reference.Month() == birth.Month() && reference.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
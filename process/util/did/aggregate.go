@@ -0,0 +1,50 @@
+package did
+
+import (
+	"math"
+	"strconv"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// 집계(aggregate) API의 COUNT 또는 SUM 컬럼에 차분 프라이버시 Laplace 노이즈를 추가하는 함수를 생성하는 함수입니다.
+// Epsilon이 지정되지 않은 경우 값을 그대로 반환하며, count는 정수로 반올림되고 음수는 0으로 보정됩니다. (Sensitivity default: count 1)
+func BuildAggregatingFunc(options model.AnoOption) func(string) string {
+	switch options.Algorithm {
+	case "count", "sum":
+	default:
+		return func(inString string) string {
+			return "unknown Aggregate algorithm"
+		}
+	}
+	if options.Epsilon == "" {
+		return func(inString string) string {
+			return inString
+		}
+	}
+
+	// Build noise function
+	sensitivity := options.Sensitivity
+	if sensitivity == "" && options.Algorithm == "count" {
+		sensitivity = "1"
+	}
+	noise := BuildNoiseFunc(model.AnoOption{
+		Algorithm:   "laplace",
+		Epsilon:     options.Epsilon,
+		Sensitivity: sensitivity,
+		Position:    options.Position,
+	})
+	if options.Algorithm == "sum" {
+		return noise
+	}
+
+	return func(inString string) string {
+		noised := noise(inString)
+		value, err := strconv.ParseFloat(noised, 64)
+		if err != nil {
+			return noised
+		}
+		return strconv.FormatFloat(math.Max(math.Round(value), 0), 'f', 0, 64)
+	}
+}
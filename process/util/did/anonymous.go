@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"math"
 	"strconv"
-	"strings"
 
 	// Model
 	model "github.com/tovdata/privacydam-go/core/model"
@@ -168,49 +167,43 @@ func BuildRangingFunc(options model.AnoOption) func(string) string {
 	}
 }
 
+// 문자(rune) 단위로 앞(Fore)과 뒤(Aft)를 제외한 부분을 마스킹하는 함수를 생성하는 함수입니다. Profile(name, email, address)이 지정된 경우 해당 프로파일의 규칙에 따라 마스킹합니다.
 func BuildMaskingFunc(options model.AnoOption) func(string) string {
-	//maskPattern = '(^.{{{startlen}}})(.*)(.{{{endlen}}}$)'
+	if options.Profile != "" {
+		return buildProfileMaskingFunc(options)
+	}
+
 	fore, err := strconv.ParseInt(options.Fore, 10, 0)
-	if err != nil {
+	if err != nil || fore < 0 {
 		return func(inString string) string {
 			return "fore parameter error"
 		}
 	}
 	aft, err1 := strconv.ParseInt(options.Aft, 10, 0)
-	if err1 != nil {
+	if err1 != nil || aft < 0 {
 		return func(inString string) string {
 			return "aft parameter error"
 		}
 	}
-	maskChar := options.MaskChar
 	keepLength, err2 := strconv.ParseBool(options.KeepLength)
 	if err2 != nil {
 		return func(inString string) string {
 			return "keepLength parameter error"
 		}
 	}
-	//reString := fmt.Sprintf("(^.{%v})(.*)(.{%v}$)", fore, aft)
-	//re := regexp.MustCompile(reString)
-
-	mask := strings.Repeat(maskChar, int(256)) // assume the Maximum Length of field is less than 256
+	// empty mask character removes the masked part (unless the length is kept)
+	maskChar := options.MaskChar
+	if maskChar == "" && keepLength {
+		maskChar = "*"
+	}
 
-	//reObject = re.compile(maskPattern.format(startlen=fore, endlen=aft))
 	return func(inString string) string {
 		if inString == "" {
 			return ""
 		}
-		if len(inString) >= int(fore+aft) {
-			//resIndex := re.FindStringSubmatchIndex(inString)
-			if keepLength {
-				//maskLen := resIndex[5] - resIndex[4]
-				maskLen := len(inString) - int(fore) - int(aft)
-				//repeatNum := math.Ceil(float64(maskLen / len(maskChar)))
-				//mask := strings.Repeat(maskChar, int(repeatNum))
-				//return inString[resIndex[2]:resIndex[3]] + mask[0:maskLen] + inString[resIndex[6]:resIndex[7]]
-				return inString[0:fore] + mask[0:maskLen] + inString[len(inString)-int(aft):]
-			}
-			//return inString[resIndex[2]:resIndex[3]] + maskChar + inString[resIndex[6]:resIndex[7]]
-			return inString[0:fore] + maskChar + inString[len(inString)-int(aft):]
+		runes := []rune(inString)
+		if len(runes) >= int(fore+aft) {
+			return maskRunes(runes, int(fore), int(aft), maskChar, keepLength)
 		}
 		return ""
 	}
@@ -0,0 +1,29 @@
+package did
+
+import (
+	"strconv"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// 차분 프라이버시 비식별 처리(noise, aggregate)로 인해 1회 처리 시 소모되는 프라이버시 예산(epsilon)을 계산하는 함수입니다. 각 컬럼의 epsilon은 순차 합성(sequential composition)에 의해 합산됩니다.
+//	# Parameters
+//	options (map[string]model.AnoParamOption): de-identification option by column
+//
+//	# Response
+//	(float64): epsilon spent by one request (0: not protected by differential privacy)
+func CalculateEpsilon(options map[string]model.AnoParamOption) float64 {
+	total := float64(0)
+	for _, option := range options {
+		for _, step := range FlattenOption(option) {
+			if step.Method != "noise" && step.Method != "aggregate" {
+				continue
+			}
+			if epsilon, err := strconv.ParseFloat(step.Options.Epsilon, 64); err == nil && epsilon > 0 {
+				total += epsilon
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+package did
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// 카드번호의 BIN(앞 6자리)과 뒤 4자리를 유지하고, 가운데 번호를 Key(hex) 기반 HMAC으로 대체하는 함수를 생성하는 함수입니다.
+// 결과가 Luhn 검증을 통과하도록 가운데 번호의 마지막 자리를 보정하며, 구분자(ex. '-', ' ')의 위치는 유지됩니다.
+func BuildCardTokenizingFunc(options model.AnoOption) func(string) string {
+	key, err := hex.DecodeString(options.Key)
+	if err != nil || len(key) == 0 {
+		return func(inString string) string {
+			return "key parameter error"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		digits := extractDigits(inString)
+		if len(digits) < 13 || len(digits) > 19 {
+			return "parseCard error:" + inString
+		}
+
+		// Replace middle digits (except BIN and last 4 digits)
+		mac := hmac.New(sha256.New, key)
+		for _, digit := range digits {
+			mac.Write([]byte{byte('0' + digit)})
+		}
+		sum := mac.Sum(nil)
+		middle := digits[6 : len(digits)-4]
+		for i := range middle {
+			middle[i] = int(sum[i] % 10)
+		}
+
+		// Adjust last middle digit to pass Luhn validation
+		last := len(middle) - 1
+		for candidate := 0; candidate < 10; candidate++ {
+			middle[last] = candidate
+			if luhnChecksum(digits) == 0 {
+				break
+			}
+		}
+
+		// Restore format
+		result := []byte(inString)
+		index := 0
+		for i, char := range result {
+			if char >= '0' && char <= '9' {
+				result[i] = byte('0' + digits[index])
+				index++
+			}
+		}
+		return string(result)
+	}
+}
@@ -0,0 +1,61 @@
+package did
+
+import (
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestCardTokenizing(t *testing.T) {
+	tokenize := BuildCardTokenizingFunc(model.AnoOption{Key: "2b7e151628aed2a6abf7158809cf4f3c"})
+	other := BuildCardTokenizingFunc(model.AnoOption{Key: "000102030405060708090a0b0c0d0e0f"})
+	for _, card := range []string{"4111-1111-1111-1111", "4111111111111111", "3782 822463 10005", "6011 0009 9013 9424 123"} {
+		output := tokenize(card)
+		if len(output) != len(card) {
+			t.Errorf("%s: format is changed (%q)", card, output)
+			continue
+		}
+		digits, tokenized := extractDigits(card), extractDigits(output)
+		for i := range card {
+			if (card[i] >= '0' && card[i] <= '9') != (output[i] >= '0' && output[i] <= '9') {
+				t.Errorf("%s: separator is moved (%q)", card, output)
+				break
+			}
+		}
+		for i := 0; i < 6; i++ {
+			if digits[i] != tokenized[i] {
+				t.Errorf("%s: BIN is changed (%q)", card, output)
+			}
+		}
+		for i := len(digits) - 4; i < len(digits); i++ {
+			if digits[i] != tokenized[i] {
+				t.Errorf("%s: last 4 digits are changed (%q)", card, output)
+			}
+		}
+		if luhnChecksum(tokenized) != 0 {
+			t.Errorf("%s: %q does not pass Luhn validation", card, output)
+		}
+		if tokenize(card) != output {
+			t.Errorf("%s: token is not stable", card)
+		}
+		if other(card) == output {
+			t.Errorf("%s: token does not depend on key", card)
+		}
+	}
+
+	for _, test := range []struct {
+		key      string
+		input    string
+		expected string
+	}{
+		{"2b7e151628aed2a6abf7158809cf4f3c", "", ""},
+		{"2b7e151628aed2a6abf7158809cf4f3c", "1234", "parseCard error:1234"},
+		{"", "4111111111111111", "key parameter error"},
+		{"raw secret", "4111111111111111", "key parameter error"},
+	} {
+		if output := BuildCardTokenizingFunc(model.AnoOption{Key: test.key})(test.input); output != test.expected {
+			t.Errorf("key %q, %q: %q, expected %q", test.key, test.input, output, test.expected)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package did
+
+import (
+	"strings"
+	"unicode"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// 여러 비식별 처리 함수를 순서대로 적용하는 함수를 생성하는 함수입니다.
+//	# Parameters
+//	funcs (...func(string) string): de-identification functions (in order)
+func ChainFuncs(funcs ...func(string) string) func(string) string {
+	return func(inString string) string {
+		for _, fn := range funcs {
+			inString = fn(inString)
+		}
+		return inString
+	}
+}
+
+// 연쇄(Chain) 옵션을 포함한 비식별 옵션을 실제로 적용되는 단일 처리 옵션 목록으로 변환하는 함수입니다.
+//	# Parameters
+//	option (model.AnoParamOption): de-identification option for column
+//
+//	# Response
+//	([]model.AnoParamOption): de-identification options (in order)
+func FlattenOption(option model.AnoParamOption) []model.AnoParamOption {
+	if len(option.Chain) == 0 {
+		return []model.AnoParamOption{option}
+	}
+	result := make([]model.AnoParamOption, 0, len(option.Chain))
+	for _, step := range option.Chain {
+		result = append(result, FlattenOption(step)...)
+	}
+	return result
+}
+
+// normalizers by algorithm name
+var normalizers = map[string]func(string) string{
+	"trim": strings.TrimSpace,
+	// collapse consecutive whitespaces
+	"space": func(inString string) string {
+		return strings.Join(strings.Fields(inString), " ")
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"digits": func(inString string) string {
+		return strings.Map(func(char rune) rune {
+			if unicode.IsDigit(char) {
+				return char
+			}
+			return -1
+		}, inString)
+	},
+}
+
+// 값을 정규화(공백 제거, 대소문자 변환, 숫자 추출 등)하는 함수를 생성하는 함수입니다. Algorithm은 쉼표로 구분된 처리 목록(trim, space, lower, upper, digits)이며, 기본값은 trim입니다.
+func BuildNormalizingFunc(options model.AnoOption) func(string) string {
+	algorithms := []string{"trim"}
+	if options.Algorithm != "" {
+		algorithms = strings.Split(options.Algorithm, ",")
+	}
+
+	steps := make([]func(string) string, len(algorithms))
+	for i, algorithm := range algorithms {
+		normalizer, ok := normalizers[strings.TrimSpace(algorithm)]
+		if !ok {
+			return func(inString string) string {
+				return "algorithm parameter error"
+			}
+		}
+		steps[i] = normalizer
+	}
+	return ChainFuncs(steps...)
+}
@@ -0,0 +1,135 @@
+package did
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// default length of pseudonymized email local part
+const defaultEmailLocalLength = 16
+
+// 이메일 주소의 로컬 파트를 Key(hex) 기반 HMAC으로 가명화하는 함수를 생성하는 함수입니다. 결과는 유효한 이메일 형식이며, 동일한 주소는 항상 동일한 가명으로 대체됩니다.
+// KeepDomain이 "true"인 경우 도메인을 유지하고, 그렇지 않은 경우 도메인도 가명화(ex. d1a2b3c4.example.com)합니다. (Length: 로컬 파트 길이, default: 16)
+func BuildEmailPseudonymizingFunc(options model.AnoOption) func(string) string {
+	key, err := hex.DecodeString(options.Key)
+	if err != nil || len(key) == 0 {
+		return func(inString string) string {
+			return "key parameter error"
+		}
+	}
+	length := defaultEmailLocalLength
+	if options.Length != "" {
+		value, err := strconv.Atoi(options.Length)
+		if err != nil || value <= 0 || value > 64 {
+			return func(inString string) string {
+				return "length parameter error"
+			}
+		}
+		length = value
+	}
+	keepDomain := false
+	if options.KeepDomain != "" {
+		value, err := strconv.ParseBool(options.KeepDomain)
+		if err != nil {
+			return func(inString string) string {
+				return "keepDomain parameter error"
+			}
+		}
+		keepDomain = value
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		index := strings.LastIndex(inString, "@")
+		if index <= 0 || index == len(inString)-1 {
+			return "parseEmail error:" + inString
+		}
+		local := pseudonymizeHex(key, "local", strings.ToLower(inString[:index]))
+		domain := inString[index+1:]
+		if !keepDomain {
+			domain = "d" + pseudonymizeHex(key, "domain", strings.ToLower(domain))[:8] + ".example.com"
+		}
+		return local[:length] + "@" + domain
+	}
+}
+
+// 전화번호의 식별번호(010, 02, 031 등)를 유지하고 나머지 번호를 Key(hex) 기반 형태 보존 암호화(FF1)로 가명화하는 함수를 생성하는 함수입니다.
+// 구분자(ex. '-')의 위치는 유지되며, 동일한 번호는 항상 동일한 번호로 대체됩니다.
+func BuildPhonePseudonymizingFunc(options model.AnoOption) func(string) string {
+	key, err := hex.DecodeString(options.Key)
+	if err != nil {
+		return func(inString string) string {
+			return "key parameter error"
+		}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return func(inString string) string {
+			return "key parameter error"
+		}
+	}
+	alphabet, _ := newFpeAlphabet(defaultAlphabet)
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		// Find end of prefix
+		prefixLength := phonePrefixLength(extractDigits(inString))
+		if prefixLength == 0 {
+			return "parsePhone error:" + inString
+		}
+		end, count := 0, 0
+		for i, char := range inString {
+			if char >= '0' && char <= '9' {
+				if count++; count == prefixLength {
+					end = i + 1
+					break
+				}
+			}
+		}
+
+		// Encrypt subscriber number (prefix is used as tweak)
+		fpe := newFF1(block, []byte(inString[:end]), 10)
+		output, err := transformFormatPreserving(inString[end:], alphabet, fpe.encrypt)
+		if err != nil {
+			return "parsePhone error:" + inString
+		}
+		return inString[:end] + output
+	}
+}
+
+// phonePrefixLength returns the length of Korean phone number prefix (0: invalid number)
+func phonePrefixLength(digits []int) int {
+	if len(digits) < 9 || len(digits) > 12 || digits[0] != 0 {
+		return 0
+	}
+	switch {
+	case digits[1] == 2:
+		// Seoul (02)
+		return 2
+	case digits[1] == 5 && digits[2] == 0:
+		// personal number (050X)
+		return 4
+	default:
+		// mobile (01X), area code (031 ~ 064), internet phone (070), toll free (080)
+		return 3
+	}
+}
+
+func pseudonymizeHex(key []byte, purpose string, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package did
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"time"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// supported date formats (the first matched layout is used to format the output)
+var dateLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+}
+
+// 레코드(row) 단위로 공유되는 난수 값입니다. 레코드 단위(scope: record)로 날짜를 이동할 때 같은 레코드의 날짜 컬럼들은 같은 값으로부터 이동 일수를 계산하므로, 이동 범위가 같다면 같은 일수만큼 이동하여 날짜 간의 간격이 유지됩니다.
+type RecordSeed struct {
+	value uint64
+}
+
+// 다음 레코드를 위한 난수 값을 생성하는 함수입니다. 각 레코드를 처리하기 전에 호출되어야 합니다.
+func (r *RecordSeed) Next() {
+	r.value = cryptoSource{}.Uint64()
+}
+
+// 날짜 데이터를 일반화하는 함수를 생성하는 함수입니다. 날짜를 단위(year, quarter, month, week)로 절삭(truncate)하거나 임의의 일수만큼 이동(shift)시킵니다.
+// 이동 범위는 Lower, Upper(일 단위)로 지정하며, Scope가 "api"인 경우 Key로부터 생성된 API 단위의 고정 값, "record"(default)인 경우 레코드마다 임의의 값(seed)만큼 이동합니다.
+//	# Parameters
+//	options (model.AnoOption): date generalization options
+//	seed (*RecordSeed): random value shared by columns of current record (required for record scope)
+func BuildDateGeneralizingFunc(options model.AnoOption, seed *RecordSeed) func(string) string {
+	var generalize func(time.Time) (time.Time, error)
+	switch options.Algorithm {
+	case "", "truncate":
+		unit := options.Unit
+		if unit != "year" && unit != "quarter" && unit != "month" && unit != "week" && unit != "day" {
+			return func(inString string) string {
+				return "unit parameter error"
+			}
+		}
+		generalize = func(date time.Time) (time.Time, error) {
+			return truncateDate(date, unit), nil
+		}
+	case "shift":
+		lower, err := strconv.ParseInt(options.Lower, 10, 0)
+		if err != nil {
+			return func(inString string) string {
+				return "lower parameter error"
+			}
+		}
+		upper, err := strconv.ParseInt(options.Upper, 10, 0)
+		if err != nil || upper < lower {
+			return func(inString string) string {
+				return "upper parameter error"
+			}
+		}
+		span := upper - lower + 1
+		switch options.Scope {
+		case "api":
+			if options.Key == "" {
+				return func(inString string) string {
+					return "key parameter error"
+				}
+			}
+			// derive a fixed offset from key
+			mac := hmac.New(sha256.New, []byte(options.Key))
+			mac.Write([]byte("date_shift"))
+			offset := lower + int64(binary.BigEndian.Uint64(mac.Sum(nil))%uint64(span))
+			generalize = func(date time.Time) (time.Time, error) {
+				return date.AddDate(0, 0, int(offset)), nil
+			}
+		case "", "record":
+			if seed == nil {
+				return func(inString string) string {
+					return "seed parameter error"
+				}
+			}
+			generalize = func(date time.Time) (time.Time, error) {
+				return date.AddDate(0, 0, int(lower+int64(seed.value%uint64(span)))), nil
+			}
+		default:
+			return func(inString string) string {
+				return "scope parameter error"
+			}
+		}
+	default:
+		return func(inString string) string {
+			return "unknown Date generalization algorithm"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		date, layout, err := parseDate(inString)
+		if err != nil {
+			return "parseDate error"
+		}
+		generalized, err := generalize(date)
+		if err != nil {
+			return "date generalization error"
+		}
+		if options.Format != "" {
+			layout = options.Format
+		}
+		return generalized.Format(layout)
+	}
+}
+
+// parseDate parses the date string with supported layouts and returns the matched layout
+func parseDate(inString string) (time.Time, string, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, inString); err == nil {
+			return date, layout, nil
+		}
+	}
+	return time.Time{}, "", errors.New("parseDate error")
+}
+
+func truncateDate(date time.Time, unit string) time.Time {
+	year, month, day := date.Date()
+	switch unit {
+	case "year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location())
+	case "quarter":
+		return time.Date(year, ((month-1)/3)*3+1, 1, 0, 0, 0, 0, date.Location())
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, date.Location())
+	case "week":
+		// ISO week (starts on monday)
+		weekday := (int(date.Weekday()) + 6) % 7
+		return time.Date(year, month, day-weekday, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	}
+}
@@ -0,0 +1,57 @@
+package did
+
+import (
+	"testing"
+	"time"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestDateShiftSharedByRecord(t *testing.T) {
+	seed := new(RecordSeed)
+	options := model.AnoOption{Algorithm: "shift", Lower: "-30", Upper: "30"}
+	admission, discharge := BuildDateGeneralizingFunc(options, seed), BuildDateGeneralizingFunc(options, seed)
+
+	offsets := make(map[int]bool)
+	for i := 0; i < 20; i++ {
+		seed.Next()
+		first, err := time.Parse("2006-01-02", admission("2021-03-01"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := time.Parse("2006-01-02", discharge("2021-03-15"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if days := second.Sub(first).Hours() / 24; days != 14 {
+			t.Fatalf("interval of record is %v days, expected 14", days)
+		}
+		offset := int(first.Sub(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		if offset < -30 || offset > 30 {
+			t.Fatalf("offset %d is out of range", offset)
+		}
+		offsets[offset] = true
+	}
+	if len(offsets) < 2 {
+		t.Error("offset is not changed by record")
+	}
+}
+
+func TestDateGeneralizing(t *testing.T) {
+	for _, test := range []struct {
+		options  model.AnoOption
+		input    string
+		expected string
+	}{
+		{model.AnoOption{Unit: "month"}, "2021-03-15", "2021-03-01"},
+		{model.AnoOption{Unit: "quarter"}, "2021-08-15 10:20:30", "2021-07-01 00:00:00"},
+		{model.AnoOption{Unit: "hour"}, "2021-03-15", "unit parameter error"},
+		{model.AnoOption{Algorithm: "shift", Lower: "-10", Upper: "10"}, "2021-03-15", "seed parameter error"},
+		{model.AnoOption{Algorithm: "shift", Lower: "-10", Upper: "10", Scope: "api"}, "2021-03-15", "key parameter error"},
+	} {
+		if output := BuildDateGeneralizingFunc(test.options, nil)(test.input); output != test.expected {
+			t.Errorf("%+v: %q, expected %q", test.options, output, test.expected)
+		}
+	}
+}
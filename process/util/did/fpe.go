@@ -0,0 +1,372 @@
+package did
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// default alphabet for format-preserving encryption (digits)
+const defaultAlphabet = "0123456789"
+
+// fpeCipher encrypts a numeral string (each numeral is an index of the alphabet)
+type fpeCipher interface {
+	encrypt(numerals []int) ([]int, error)
+	decrypt(numerals []int) ([]int, error)
+}
+
+// 형태 보존 암호화(FF1 또는 FF3-1) 함수를 생성하는 함수입니다. Key와 Tweak은 hex 문자열이며, Alphabet에 포함된 문자만 암호화하므로 구분자(ex. '-')의 위치는 그대로 유지됩니다.
+func BuildFormatPreservingFunc(options model.AnoOption) func(string) string {
+	alphabet, fpe, err := buildFpeCipher(options)
+	if err != nil {
+		return func(inString string) string {
+			return err.Error()
+		}
+	}
+
+	return func(inString string) string {
+		output, err := transformFormatPreserving(inString, alphabet, fpe.encrypt)
+		if err != nil {
+			return err.Error()
+		}
+		return output
+	}
+}
+
+// fpeAlphabet maps the characters of the alphabet to numerals and back
+type fpeAlphabet struct {
+	chars   []rune
+	indexes map[rune]int
+}
+
+func newFpeAlphabet(alphabet string) (fpeAlphabet, error) {
+	if alphabet == "" {
+		alphabet = defaultAlphabet
+	}
+	result := fpeAlphabet{
+		chars:   []rune(alphabet),
+		indexes: make(map[rune]int),
+	}
+	for i, char := range result.chars {
+		if _, ok := result.indexes[char]; ok {
+			return result, errors.New("alphabet parameter error")
+		}
+		result.indexes[char] = i
+	}
+	if len(result.chars) < 2 || len(result.chars) > 65536 {
+		return result, errors.New("alphabet parameter error")
+	}
+	return result, nil
+}
+
+func buildFpeCipher(options model.AnoOption) (fpeAlphabet, fpeCipher, error) {
+	alphabet, err := newFpeAlphabet(options.Alphabet)
+	if err != nil {
+		return alphabet, nil, err
+	}
+	key, err := hex.DecodeString(options.Key)
+	if err != nil {
+		return alphabet, nil, errors.New("key parameter error")
+	}
+	tweak, err := hex.DecodeString(options.Tweak)
+	if err != nil {
+		return alphabet, nil, errors.New("tweak parameter error")
+	}
+
+	radix := len(alphabet.chars)
+	switch options.Algorithm {
+	case "", "ff1":
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return alphabet, nil, errors.New("key parameter error")
+		}
+		return alphabet, newFF1(block, tweak, radix), nil
+	case "ff3-1":
+		if len(tweak) != 7 {
+			return alphabet, nil, errors.New("tweak parameter error")
+		}
+		block, err := aes.NewCipher(reverseBytes(key))
+		if err != nil {
+			return alphabet, nil, errors.New("key parameter error")
+		}
+		// split 56-bit tweak: TL = T[0..27] || 0000, TR = T[32..55] || T[28..31] || 0000
+		tl := []byte{tweak[0], tweak[1], tweak[2], tweak[3] & 0xF0}
+		tr := []byte{tweak[4], tweak[5], tweak[6], (tweak[3] & 0x0F) << 4}
+		return alphabet, newFF3(block, tl, tr, radix), nil
+	default:
+		return alphabet, nil, errors.New("unknown FPE algorithm")
+	}
+}
+
+// transformFormatPreserving applies the cipher function to the characters in the alphabet and keeps the others at the same position
+func transformFormatPreserving(inString string, alphabet fpeAlphabet, transform func([]int) ([]int, error)) (string, error) {
+	runes := []rune(inString)
+	numerals := make([]int, 0, len(runes))
+	positions := make([]int, 0, len(runes))
+	for i, char := range runes {
+		if index, ok := alphabet.indexes[char]; ok {
+			numerals = append(numerals, index)
+			positions = append(positions, i)
+		}
+	}
+	if len(numerals) == 0 {
+		return inString, nil
+	}
+
+	transformed, err := transform(numerals)
+	if err != nil {
+		return "", err
+	}
+	for i, position := range positions {
+		runes[position] = alphabet.chars[transformed[i]]
+	}
+	return string(runes), nil
+}
+
+// minimum numeral string length to satisfy radix^minlen >= 1,000,000 (NIST SP 800-38G Rev.1)
+func fpeMinLength(radix int) int {
+	minLen := int(math.Ceil(6 / math.Log10(float64(radix))))
+	if minLen < 2 {
+		minLen = 2
+	}
+	return minLen
+}
+
+// NUM_radix(X)
+func numRadix(numerals []int, radix int) *big.Int {
+	result := new(big.Int)
+	bigRadix := big.NewInt(int64(radix))
+	for _, numeral := range numerals {
+		result.Mul(result, bigRadix)
+		result.Add(result, big.NewInt(int64(numeral)))
+	}
+	return result
+}
+
+// STR_radix^m(x)
+func strRadix(value *big.Int, radix int, m int) []int {
+	result := make([]int, m)
+	bigRadix := big.NewInt(int64(radix))
+	remain := new(big.Int).Set(value)
+	mod := new(big.Int)
+	for i := m - 1; i >= 0; i-- {
+		remain.DivMod(remain, bigRadix, mod)
+		result[i] = int(mod.Int64())
+	}
+	return result
+}
+
+func reverseNumerals(numerals []int) []int {
+	result := make([]int, len(numerals))
+	for i, numeral := range numerals {
+		result[len(numerals)-1-i] = numeral
+	}
+	return result
+}
+
+func reverseBytes(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, value := range data {
+		result[len(data)-1-i] = value
+	}
+	return result
+}
+
+// FF1 mode (NIST SP 800-38G)
+type ff1 struct {
+	block  cipher.Block
+	tweak  []byte
+	radix  int
+	minLen int
+}
+
+func newFF1(block cipher.Block, tweak []byte, radix int) *ff1 {
+	return &ff1{
+		block:  block,
+		tweak:  tweak,
+		radix:  radix,
+		minLen: fpeMinLength(radix),
+	}
+}
+
+func (f *ff1) encrypt(numerals []int) ([]int, error) {
+	return f.transform(numerals, true)
+}
+
+func (f *ff1) decrypt(numerals []int) ([]int, error) {
+	return f.transform(numerals, false)
+}
+
+func (f *ff1) transform(numerals []int, encrypt bool) ([]int, error) {
+	n := len(numerals)
+	if n < f.minLen {
+		return nil, errors.New("fpe input length error")
+	}
+	t := len(f.tweak)
+	u := n / 2
+	v := n - u
+	A := append([]int{}, numerals[:u]...)
+	B := append([]int{}, numerals[u:]...)
+
+	b := int(math.Ceil(math.Ceil(float64(v)*math.Log2(float64(f.radix))) / 8))
+	d := 4*((b+3)/4) + 4
+
+	// P = [1]^1 || [2]^1 || [1]^1 || [radix]^3 || [10]^1 || [u mod 256]^1 || [n]^4 || [t]^4
+	P := []byte{1, 2, 1, byte(f.radix >> 16), byte(f.radix >> 8), byte(f.radix), 10, byte(u), byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n), byte(t >> 24), byte(t >> 16), byte(t >> 8), byte(t)}
+	pad := (16 - (t+b+1)%16) % 16
+	Q := make([]byte, t+pad+1+b)
+	copy(Q, f.tweak)
+
+	bigRadix := big.NewInt(int64(f.radix))
+	modU := new(big.Int).Exp(bigRadix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(bigRadix, big.NewInt(int64(v)), nil)
+
+	R := make([]byte, 16)
+	S := make([]byte, ((d+15)/16)*16)
+	block := make([]byte, 16)
+	for round := 0; round < 10; round++ {
+		i := round
+		if !encrypt {
+			i = 9 - round
+		}
+		// Q = T || [0]^pad || [i]^1 || [NUM_radix(B or A)]^b
+		Q[t+pad] = byte(i)
+		source := B
+		if !encrypt {
+			source = A
+		}
+		numRadix(source, f.radix).FillBytes(Q[t+pad+1:])
+
+		// R = PRF(P || Q)
+		f.prf(R, P, Q)
+		// S = first d bytes of R || CIPH(R xor [1]^16) || CIPH(R xor [2]^16) ...
+		copy(S, R)
+		for j := 1; j < len(S)/16; j++ {
+			copy(block, R)
+			for k := 0; k < 8; k++ {
+				block[15-k] ^= byte(uint64(j) >> (8 * k))
+			}
+			f.block.Encrypt(S[16*j:16*(j+1)], block)
+		}
+		y := new(big.Int).SetBytes(S[:d])
+
+		m := u
+		mod := modU
+		if i%2 == 1 {
+			m = v
+			mod = modV
+		}
+		c := new(big.Int)
+		if encrypt {
+			c.Add(numRadix(A, f.radix), y)
+			c.Mod(c, mod)
+			A, B = B, strRadix(c, f.radix, m)
+		} else {
+			c.Sub(numRadix(B, f.radix), y)
+			c.Mod(c, mod)
+			A, B = strRadix(c, f.radix, m), A
+		}
+	}
+	return append(A, B...), nil
+}
+
+// CBC-MAC with zero IV
+func (f *ff1) prf(dst []byte, blocks ...[]byte) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for _, data := range blocks {
+		for offset := 0; offset < len(data); offset += 16 {
+			for k := 0; k < 16; k++ {
+				dst[k] ^= data[offset+k]
+			}
+			f.block.Encrypt(dst, dst)
+		}
+	}
+}
+
+// FF3-1 mode (NIST SP 800-38G Rev.1)
+type ff3 struct {
+	block  cipher.Block
+	tl, tr []byte
+	radix  int
+	minLen int
+	maxLen int
+}
+
+func newFF3(block cipher.Block, tl []byte, tr []byte, radix int) *ff3 {
+	return &ff3{
+		block:  block,
+		tl:     tl,
+		tr:     tr,
+		radix:  radix,
+		minLen: fpeMinLength(radix),
+		maxLen: 2 * int(math.Floor(96/math.Log2(float64(radix)))),
+	}
+}
+
+func (f *ff3) encrypt(numerals []int) ([]int, error) {
+	return f.transform(numerals, true)
+}
+
+func (f *ff3) decrypt(numerals []int) ([]int, error) {
+	return f.transform(numerals, false)
+}
+
+func (f *ff3) transform(numerals []int, encrypt bool) ([]int, error) {
+	n := len(numerals)
+	if n < f.minLen || n > f.maxLen {
+		return nil, errors.New("fpe input length error")
+	}
+	v := n / 2
+	u := n - v
+	A := append([]int{}, numerals[:u]...)
+	B := append([]int{}, numerals[u:]...)
+
+	bigRadix := big.NewInt(int64(f.radix))
+	modU := new(big.Int).Exp(bigRadix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(bigRadix, big.NewInt(int64(v)), nil)
+
+	P := make([]byte, 16)
+	S := make([]byte, 16)
+	for round := 0; round < 8; round++ {
+		i := round
+		if !encrypt {
+			i = 7 - round
+		}
+		m, mod, W := u, modU, f.tr
+		if i%2 == 1 {
+			m, mod, W = v, modV, f.tl
+		}
+		// P = W xor [i]^4 || [NUM_radix(REV(B or A))]^12
+		copy(P, W)
+		P[3] ^= byte(i)
+		source := B
+		if !encrypt {
+			source = A
+		}
+		numRadix(reverseNumerals(source), f.radix).FillBytes(P[4:])
+
+		// y = NUM(REVB(CIPH_REVB(K)(REVB(P))))
+		f.block.Encrypt(S, reverseBytes(P))
+		y := new(big.Int).SetBytes(reverseBytes(S))
+
+		c := new(big.Int)
+		if encrypt {
+			c.Add(numRadix(reverseNumerals(A), f.radix), y)
+			c.Mod(c, mod)
+			A, B = B, reverseNumerals(strRadix(c, f.radix, m))
+		} else {
+			c.Sub(numRadix(reverseNumerals(B), f.radix), y)
+			c.Mod(c, mod)
+			A, B = reverseNumerals(strRadix(c, f.radix, m)), A
+		}
+	}
+	return append(A, B...), nil
+}
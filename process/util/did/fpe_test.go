@@ -0,0 +1,85 @@
+package did
+
+import (
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// known-answer vectors from NIST SP 800-38G samples
+var fpeVectors = []struct {
+	name      string
+	options   model.AnoOption
+	plain     string
+	encrypted string
+}{
+	{
+		name:      "FF1 sample 1",
+		options:   model.AnoOption{Algorithm: "ff1", Key: "2B7E151628AED2A6ABF7158809CF4F3C"},
+		plain:     "0123456789",
+		encrypted: "2433477484",
+	},
+	{
+		name:      "FF1 sample 2",
+		options:   model.AnoOption{Algorithm: "ff1", Key: "2B7E151628AED2A6ABF7158809CF4F3C", Tweak: "39383736353433323130"},
+		plain:     "0123456789",
+		encrypted: "6124200773",
+	},
+	{
+		name:      "FF1 sample 3",
+		options:   model.AnoOption{Algorithm: "ff1", Key: "2B7E151628AED2A6ABF7158809CF4F3C", Tweak: "3737373770717273373737", Alphabet: "0123456789abcdefghijklmnopqrstuvwxyz"},
+		plain:     "0123456789abcdefghi",
+		encrypted: "a9tv40mll9kdu509eum",
+	},
+	{
+		name:      "FF3-1 sample",
+		options:   model.AnoOption{Algorithm: "ff3-1", Key: "EF4359D8D580AA4F7F036D6F04FC6A94", Tweak: "D8E7920AFA330A"},
+		plain:     "890121234567890000",
+		encrypted: "477064185124354662",
+	},
+}
+
+func TestFormatPreservingVectors(t *testing.T) {
+	for _, vector := range fpeVectors {
+		encrypt := BuildFormatPreservingFunc(vector.options)
+		if output := encrypt(vector.plain); output != vector.encrypted {
+			t.Errorf("%s: encrypted %q, expected %q", vector.name, output, vector.encrypted)
+		}
+
+		decrypt, err := BuildReIdentifyingFunc("fpe", vector.options)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.name, err)
+		}
+		if output, err := decrypt(vector.encrypted); err != nil || output != vector.plain {
+			t.Errorf("%s: decrypted %q (%v), expected %q", vector.name, output, err, vector.plain)
+		}
+	}
+}
+
+func TestFormatPreservingRoundTrip(t *testing.T) {
+	for _, algorithm := range []string{"ff1", "ff3-1"} {
+		options := model.AnoOption{Algorithm: algorithm, Key: "2B7E151628AED2A6ABF7158809CF4F3C", Tweak: "00010203040506"}
+		encrypt := BuildFormatPreservingFunc(options)
+		decrypt, err := BuildReIdentifyingFunc("fpe", options)
+		if err != nil {
+			t.Fatalf("%s: %v", algorithm, err)
+		}
+
+		for _, plain := range []string{"900101-1234567", "010-1234-5678", "4111 1111 1111 1111"} {
+			encrypted := encrypt(plain)
+			if encrypted == plain || len(encrypted) != len(plain) {
+				t.Errorf("%s: encrypted %q from %q", algorithm, encrypted, plain)
+			}
+			for i := range plain {
+				if (plain[i] >= '0' && plain[i] <= '9') != (encrypted[i] >= '0' && encrypted[i] <= '9') {
+					t.Errorf("%s: format of %q is not preserved (%q)", algorithm, plain, encrypted)
+					break
+				}
+			}
+			if output, err := decrypt(encrypted); err != nil || output != plain {
+				t.Errorf("%s: decrypted %q (%v), expected %q", algorithm, output, err, plain)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package did
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// meters per degree of latitude
+const metersPerDegree = 111320.0
+
+// default geohash precision
+const defaultGeohashPrecision = 6
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// simplified administrative region (si/do) boundaries (GeoJSON features with "name" property)
+// regions are tested in order, so that cities are listed before the provinces enclosing them
+//go:embed data/regions.geojson
+var rawRegions []byte
+
+type region struct {
+	name string
+	// polygons of rings ([longitude, latitude], first ring: exterior, others: holes)
+	polygons [][][][2]float64
+}
+
+var regions = loadRegions(rawRegions)
+
+// 위치 좌표를 일반화하는 함수를 생성하는 함수입니다. 입력 값은 "위도,경도" 쌍이며, Axis(lat, lng)가 지정된 경우 단일 좌표 컬럼으로 처리합니다.
+// Algorithm이 "grid"(default)인 경우 Grid(m) 크기의 격자 중심으로, "geohash"인 경우 Position(default: 6) 자리의 geohash로, "region"인 경우 좌표를 포함하는 행정구역(시/도)으로 변환하며, 포함하는 행정구역이 없는 경우(ex. 국외 좌표) "*"로 대체합니다.
+// (단일 경도 컬럼의 격자 크기는 적도 기준으로 계산되며, geohash와 region은 좌표 쌍만 지원합니다.)
+func BuildGeoGeneralizingFunc(options model.AnoOption) func(string) string {
+	if options.Axis != "" && options.Axis != "lat" && options.Axis != "lng" {
+		return func(inString string) string {
+			return "axis parameter error"
+		}
+	}
+
+	var generalize func(float64, float64) string
+	switch options.Algorithm {
+	case "", "grid":
+		grid, err := strconv.ParseFloat(options.Grid, 64)
+		if err != nil || grid <= 0 {
+			return func(inString string) string {
+				return "grid parameter error"
+			}
+		}
+		latStep := grid / metersPerDegree
+		if options.Axis != "" {
+			limit := 90.0
+			if options.Axis == "lng" {
+				limit = 180
+			}
+			return buildAxisFunc(func(value float64) string {
+				return formatCoordinate(snapToGrid(value, latStep))
+			}, limit)
+		}
+		generalize = func(latitude float64, longitude float64) string {
+			latitude = snapToGrid(latitude, latStep)
+			// longitude step by latitude of grid center
+			lngStep := latStep / math.Max(math.Cos(latitude*math.Pi/180), 1e-6)
+			return formatCoordinate(latitude) + "," + formatCoordinate(snapToGrid(longitude, lngStep))
+		}
+	case "geohash":
+		precision := options.Position
+		if precision <= 0 {
+			precision = defaultGeohashPrecision
+		} else if precision > 12 {
+			return func(inString string) string {
+				return "position parameter error"
+			}
+		}
+		generalize = func(latitude float64, longitude float64) string {
+			return encodeGeohash(latitude, longitude, precision)
+		}
+	case "region":
+		generalize = containingRegion
+	default:
+		return func(inString string) string {
+			return "unknown Geo generalization algorithm"
+		}
+	}
+	// Single coordinate column is supported by grid only
+	if options.Axis != "" {
+		return func(inString string) string {
+			return "axis parameter error"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		latitude, longitude, err := parseCoordinates(inString)
+		if err != nil {
+			return "parseCoordinates error:" + inString
+		}
+		return generalize(latitude, longitude)
+	}
+}
+
+func buildAxisFunc(generalize func(float64) string, limit float64) func(string) string {
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(inString), 64)
+		if err != nil || math.Abs(value) > limit {
+			return "parseFloat error:" + inString
+		}
+		return generalize(value)
+	}
+}
+
+// parseCoordinates parses "latitude,longitude" (separated by comma or space)
+func parseCoordinates(inString string) (float64, float64, error) {
+	fields := strings.FieldsFunc(inString, func(char rune) bool {
+		return char == ',' || unicode.IsSpace(char)
+	})
+	if len(fields) != 2 {
+		return 0, 0, errors.New("coordinate format error")
+	}
+	latitude, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || math.Abs(latitude) > 90 {
+		return 0, 0, errors.New("latitude format error")
+	}
+	longitude, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || math.Abs(longitude) > 180 {
+		return 0, 0, errors.New("longitude format error")
+	}
+	return latitude, longitude, nil
+}
+
+// snapToGrid returns the center of grid cell containing the value
+func snapToGrid(value float64, step float64) float64 {
+	return math.Floor(value/step)*step + step/2
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+func encodeGeohash(latitude float64, longitude float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	result := make([]byte, 0, precision)
+	even := true
+	bit, index := 0, 0
+	for len(result) < precision {
+		if even {
+			if middle := (lngRange[0] + lngRange[1]) / 2; longitude >= middle {
+				index = index<<1 | 1
+				lngRange[0] = middle
+			} else {
+				index <<= 1
+				lngRange[1] = middle
+			}
+		} else {
+			if middle := (latRange[0] + latRange[1]) / 2; latitude >= middle {
+				index = index<<1 | 1
+				latRange[0] = middle
+			} else {
+				index <<= 1
+				latRange[1] = middle
+			}
+		}
+		even = !even
+		if bit++; bit == 5 {
+			result = append(result, geohashAlphabet[index])
+			bit, index = 0, 0
+		}
+	}
+	return string(result)
+}
+
+// containingRegion returns the name of region containing the coordinates ("*": not contained in any region)
+func containingRegion(latitude float64, longitude float64) string {
+	for _, candidate := range regions {
+		for _, polygon := range candidate.polygons {
+			if polygonContains(polygon, longitude, latitude) {
+				return candidate.name
+			}
+		}
+	}
+	return "*"
+}
+
+func polygonContains(polygon [][][2]float64, x float64, y float64) bool {
+	if len(polygon) == 0 || !ringContains(polygon[0], x, y) {
+		return false
+	}
+	for _, hole := range polygon[1:] {
+		if ringContains(hole, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains tests whether the point is inside the ring (ray casting)
+func ringContains(ring [][2]float64, x float64, y float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if (ring[i][1] > y) != (ring[j][1] > y) && x < (ring[j][0]-ring[i][0])*(y-ring[i][1])/(ring[j][1]-ring[i][1])+ring[i][0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func loadRegions(raw []byte) []region {
+	var collection struct {
+		Features []struct {
+			Properties struct {
+				Name string `json:"name"`
+			} `json:"properties"`
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(raw, &collection); err != nil {
+		panic(err)
+	}
+	result := make([]region, 0, len(collection.Features))
+	for _, feature := range collection.Features {
+		var polygons [][][][2]float64
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var polygon [][][2]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+				panic(err)
+			}
+			polygons = append(polygons, polygon)
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+				panic(err)
+			}
+		default:
+			panic("unsupported region geometry: " + feature.Geometry.Type)
+		}
+		result = append(result, region{name: feature.Properties.Name, polygons: polygons})
+	}
+	return result
+}
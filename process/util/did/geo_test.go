@@ -0,0 +1,60 @@
+package did
+
+import (
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestGeoRegion(t *testing.T) {
+	generalize := BuildGeoGeneralizingFunc(model.AnoOption{Algorithm: "region"})
+	for _, test := range []struct {
+		place       string
+		coordinates string
+		expected    string
+	}{
+		{"Seoul city hall", "37.5665,126.9780", "서울특별시"},
+		{"Guro-gu office", "37.4954,126.8874", "서울특별시"},
+		// cities bordering Seoul
+		{"Suwon", "37.2636,127.0286", "경기도"},
+		{"Goyang", "37.6584,126.8320", "경기도"},
+		{"Bucheon", "37.5034,126.7660", "경기도"},
+		{"Gwangmyeong", "37.4786,126.8646", "경기도"},
+		{"Gwacheon", "37.4292,126.9876", "경기도"},
+		{"Guri", "37.5943,127.1296", "경기도"},
+		{"Gimpo", "37.6153,126.7156", "경기도"},
+		{"Incheon city hall", "37.4563,126.7052", "인천광역시"},
+		{"Incheon airport", "37.4602,126.4407", "인천광역시"},
+		{"Sejong city hall", "36.4800,127.2890", "세종특별자치시"},
+		{"Gongju", "36.4465,127.1190", "충청남도"},
+		{"Daejeon city hall", "36.3504,127.3845", "대전광역시"},
+		{"Gunwi", "36.2428,128.5728", "대구광역시"},
+		{"Gyeongsan", "35.8251,128.7411", "경상북도"},
+		{"Yangsan", "35.3350,129.0372", "경상남도"},
+		{"Busan city hall", "35.1796,129.0756", "부산광역시"},
+		{"Ulsan city hall", "35.5384,129.3114", "울산광역시"},
+		{"Hadong", "35.0673,127.7513", "경상남도"},
+		{"Gwangyang", "34.9407,127.6959", "전라남도"},
+		{"Sokcho", "38.2070,128.5918", "강원특별자치도"},
+		{"Ulleung", "37.4844,130.9057", "경상북도"},
+		{"Seogwipo", "33.2541,126.5600", "제주특별자치도"},
+		// outside of coverage area
+		{"Kaesong", "37.9700,126.5500", "*"},
+		{"Pyongyang", "39.0392,125.7625", "*"},
+		{"Tsushima", "34.4000,129.3000", "*"},
+		{"Tokyo", "35.6895,139.6917", "*"},
+		{"Yellow sea", "36.5000,125.8000", "*"},
+	} {
+		if output := generalize(test.coordinates); output != test.expected {
+			t.Errorf("%s: %q, expected %q", test.place, output, test.expected)
+		}
+	}
+}
+
+func TestGeoGeohash(t *testing.T) {
+	generalize := BuildGeoGeneralizingFunc(model.AnoOption{Algorithm: "geohash", Position: 7})
+	if output := generalize("57.64911,10.40744"); output != "u4pruyd" {
+		t.Errorf("geohash %q, expected %q", output, "u4pruyd")
+	}
+}
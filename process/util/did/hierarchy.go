@@ -0,0 +1,35 @@
+package did
+
+// 사용자 정의 분류 체계(taxonomy)로써 각 노드(key)의 상위 노드(value)를 정의합니다. 최상위 노드의 상위 노드는 빈 문자열입니다.
+type Taxonomy map[string]string
+
+// 분류 체계(taxonomy)를 이용하여 값을 상위 노드로 일반화하는 함수를 생성하는 함수입니다. level 만큼 상위 노드로 이동하며, 최상위 노드에 도달한 경우 최상위 노드를 반환합니다.
+// 분류 체계에 존재하지 않는 값은 식별 위험이 있으므로 "*"로 대체됩니다.
+//	# Parameters
+//	level (int): generalization level (= count of steps to parent)
+//	taxonomy (Taxonomy): loaded taxonomy
+func BuildHierarchyFunc(level int, taxonomy Taxonomy) func(string) string {
+	if level < 0 {
+		return func(inString string) string {
+			return "level parameter error"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		if _, ok := taxonomy[inString]; !ok {
+			return "*"
+		}
+		node := inString
+		for i := 0; i < level; i++ {
+			parent, ok := taxonomy[node]
+			if !ok || parent == "" {
+				break
+			}
+			node = parent
+		}
+		return node
+	}
+}
@@ -0,0 +1,141 @@
+package did
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// default prefix length to keep by IP version
+const (
+	defaultPrefixV4 = 24
+	defaultPrefixV6 = 48
+)
+
+// IP 주소를 익명화하는 함수를 생성하는 함수입니다. Algorithm이 "truncate"(default)인 경우 PrefixV4(default: 24), PrefixV6(default: 48) 비트 이후를 0으로 절삭하며,
+// "cryptopan"인 경우 Key(hex, 32 bytes)를 이용하여 접두사 보존(prefix-preserving) 가명화를 수행합니다. (cryptopan에서 PrefixV4, PrefixV6가 지정된 경우 가명화 후 절삭)
+func BuildIpAnonymizingFunc(options model.AnoOption) func(string) string {
+	prefixV4, prefixV6, err := parsePrefixes(options)
+	if err != nil {
+		return func(inString string) string {
+			return err.Error()
+		}
+	}
+
+	var pseudonymize func(net.IP) net.IP
+	switch options.Algorithm {
+	case "", "truncate":
+		if prefixV4 < 0 {
+			prefixV4 = defaultPrefixV4
+		}
+		if prefixV6 < 0 {
+			prefixV6 = defaultPrefixV6
+		}
+	case "cryptopan":
+		pan, err := newCryptoPan(options.Key)
+		if err != nil {
+			return func(inString string) string {
+				return err.Error()
+			}
+		}
+		pseudonymize = pan.anonymize
+	default:
+		return func(inString string) string {
+			return "unknown IP anonymization algorithm"
+		}
+	}
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		ip := net.ParseIP(inString)
+		if ip == nil {
+			return "parseIP error:" + inString
+		}
+		prefix := prefixV6
+		if v4 := ip.To4(); v4 != nil {
+			ip, prefix = v4, prefixV4
+		}
+		if pseudonymize != nil {
+			ip = pseudonymize(ip)
+		}
+		if prefix >= 0 {
+			ip = ip.Mask(net.CIDRMask(prefix, len(ip)*8))
+		}
+		return ip.String()
+	}
+}
+
+// parsePrefixes returns the prefix length by IP version (-1: not specified)
+func parsePrefixes(options model.AnoOption) (int, int, error) {
+	prefixV4, prefixV6 := -1, -1
+	if options.PrefixV4 != "" {
+		value, err := strconv.Atoi(options.PrefixV4)
+		if err != nil || value < 0 || value > 32 {
+			return 0, 0, errors.New("prefixV4 parameter error")
+		}
+		prefixV4 = value
+	}
+	if options.PrefixV6 != "" {
+		value, err := strconv.Atoi(options.PrefixV6)
+		if err != nil || value < 0 || value > 128 {
+			return 0, 0, errors.New("prefixV6 parameter error")
+		}
+		prefixV6 = value
+	}
+	return prefixV4, prefixV6, nil
+}
+
+// Crypto-PAn prefix-preserving IP address pseudonymization (Xu et al.)
+type cryptoPan struct {
+	block cipher.Block
+	pad   []byte
+}
+
+func newCryptoPan(hexKey string) (*cryptoPan, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("key parameter error")
+	}
+	// first 16 bytes: AES key, last 16 bytes: pad seed
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, errors.New("key parameter error")
+	}
+	pad := make([]byte, aes.BlockSize)
+	block.Encrypt(pad, key[16:])
+	return &cryptoPan{block: block, pad: pad}, nil
+}
+
+// anonymize maps the address so that addresses sharing a k-bit prefix keep sharing a k-bit prefix
+func (c *cryptoPan) anonymize(ip net.IP) net.IP {
+	bits := len(ip) * 8
+	input := make([]byte, aes.BlockSize)
+	output := make([]byte, aes.BlockSize)
+	result := make(net.IP, len(ip))
+	for position := 0; position < bits; position++ {
+		// input = first (position) bits of address || remaining bits of pad
+		copy(input, c.pad)
+		for i := 0; i < position/8; i++ {
+			input[i] = ip[i]
+		}
+		if remain := position % 8; remain > 0 {
+			mask := byte(0xFF << (8 - remain))
+			input[position/8] = (ip[position/8] & mask) | (c.pad[position/8] &^ mask)
+		}
+		c.block.Encrypt(output, input)
+		// flip bit by the most significant bit of output
+		result[position/8] |= (output[0] >> 7) << (7 - position%8)
+	}
+	for i := range result {
+		result[i] ^= ip[i]
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package did
+
+import (
+	"strconv"
+	"strings"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// buildProfileMaskingFunc creates a masking function by profile (name, email, address) (mask character default: "*")
+func buildProfileMaskingFunc(options model.AnoOption) func(string) string {
+	maskChar := options.MaskChar
+	if maskChar == "" {
+		maskChar = "*"
+	}
+	fore, err := parseOptionalInt(options.Fore, -1)
+	if err != nil {
+		return func(inString string) string {
+			return "fore parameter error"
+		}
+	}
+	aft, err := parseOptionalInt(options.Aft, -1)
+	if err != nil {
+		return func(inString string) string {
+			return "aft parameter error"
+		}
+	}
+	keepLength := true
+	if options.KeepLength != "" {
+		if keepLength, err = strconv.ParseBool(options.KeepLength); err != nil {
+			return func(inString string) string {
+				return "keepLength parameter error"
+			}
+		}
+	}
+
+	switch options.Profile {
+	case "name":
+		// ex. 홍길동 -> 홍*동, 김철 -> 김*
+		return func(inString string) string {
+			return maskName(strings.TrimSpace(inString), fore, aft, maskChar, keepLength)
+		}
+	case "email":
+		// ex. hong@example.com -> h***@example.com
+		return func(inString string) string {
+			inString = strings.TrimSpace(inString)
+			index := strings.LastIndex(inString, "@")
+			if index < 0 {
+				return maskName(inString, fore, aft, maskChar, keepLength)
+			}
+			localAft := aft
+			if localAft < 0 {
+				localAft = 0
+			}
+			return maskName(inString[:index], fore, localAft, maskChar, keepLength) + inString[index:]
+		}
+	case "address":
+		// ex. 서울특별시 강남구 테헤란로 123 -> 서울특별시 강남구 **** ***
+		return func(inString string) string {
+			return maskAddress(inString, maskChar, keepLength)
+		}
+	default:
+		return func(inString string) string {
+			return "profile parameter error"
+		}
+	}
+}
+
+// maskName keeps the first (fore) and last (aft) characters and masks at least one character
+func maskName(inString string, fore int, aft int, maskChar string, keepLength bool) string {
+	runes := []rune(inString)
+	if len(runes) == 0 {
+		return ""
+	}
+	if fore < 0 {
+		fore = 1
+	}
+	if aft < 0 {
+		if len(runes) >= 3 {
+			aft = 1
+		} else {
+			aft = 0
+		}
+	}
+	if fore+aft >= len(runes) {
+		aft = 0
+		if fore >= len(runes) {
+			fore = len(runes) - 1
+		}
+	}
+	return maskRunes(runes, fore, aft, maskChar, keepLength)
+}
+
+// maskAddress keeps the address up to district (시/군/구) and masks the rest
+func maskAddress(inString string, maskChar string, keepLength bool) string {
+	fields := strings.Fields(inString)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	// Keep province and following district tokens
+	keep := 1
+	for keep < len(fields) && isDistrict(fields[keep]) {
+		keep++
+	}
+	if keep == len(fields) {
+		return strings.Join(fields, " ")
+	}
+
+	masked := make([]string, 0, len(fields)-keep)
+	if keepLength {
+		for _, field := range fields[keep:] {
+			masked = append(masked, strings.Repeat(maskChar, len([]rune(field))))
+		}
+	} else {
+		masked = append(masked, maskChar)
+	}
+	return strings.Join(fields[:keep], " ") + " " + strings.Join(masked, " ")
+}
+
+func isDistrict(field string) bool {
+	return strings.HasSuffix(field, "시") || strings.HasSuffix(field, "군") || strings.HasSuffix(field, "구")
+}
+
+// maskRunes keeps the first (fore) and last (aft) runes and replaces the others with mask
+func maskRunes(runes []rune, fore int, aft int, maskChar string, keepLength bool) string {
+	maskLen := 1
+	if keepLength {
+		maskLen = len(runes) - fore - aft
+	}
+	return string(runes[:fore]) + strings.Repeat(maskChar, maskLen) + string(runes[len(runes)-aft:])
+}
+
+func parseOptionalInt(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil || result < 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package did
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+var (
+	noiseSource      func() rand.Source = newCryptoSource
+	noiseSourceMutex                    = &sync.Mutex{}
+)
+
+// cryptoSource is a rand.Source64 backed by crypto/rand (Seed is ignored)
+type cryptoSource struct{}
+
+func newCryptoSource() rand.Source {
+	return cryptoSource{}
+}
+
+func (s cryptoSource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+func (s cryptoSource) Uint64() uint64 {
+	var buffer [8]byte
+	if _, err := crand.Read(buffer[:]); err != nil {
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(buffer[:])
+}
+
+func (s cryptoSource) Seed(seed int64) {}
+
+// 노이즈 생성에 사용할 난수 생성기를 설정하는 함수입니다. 기본적으로 암호학적 난수 생성기(crypto/rand)를 사용하며, 테스트와 같이 재현 가능한 결과가 필요한 경우에만 시드가 지정된 rand.Source를 생성하는 함수를 설정합니다.
+//	# Parameters
+//	factory (func() rand.Source): source factory (nil: crypto/rand source)
+func SetNoiseSource(factory func() rand.Source) {
+	noiseSourceMutex.Lock()
+	defer noiseSourceMutex.Unlock()
+
+	if factory == nil {
+		noiseSource = newCryptoSource
+	} else {
+		noiseSource = factory
+	}
+}
+
+func newNoiseGenerator() *rand.Rand {
+	noiseSourceMutex.Lock()
+	defer noiseSourceMutex.Unlock()
+
+	return rand.New(noiseSource())
+}
+
+// 차분 프라이버시(differential privacy)를 위해 수치 데이터에 Laplace 또는 Gaussian 노이즈를 추가하는 함수를 생성하는 함수입니다.
+// 노이즈의 크기는 Epsilon, Delta(gaussian), Sensitivity로 결정되며, 출력 소수점 자리수는 Position(default: 입력 값의 자리수)을 따릅니다. Gaussian 노이즈의 Epsilon은 1 미만이어야 합니다.
+func BuildNoiseFunc(options model.AnoOption) func(string) string {
+	noise, err := buildNoiseGenerator(options)
+	if err != nil {
+		return func(inString string) string {
+			return err.Error()
+		}
+	}
+
+	return func(inString string) string {
+		value, err := strconv.ParseFloat(inString, 64)
+		if err != nil {
+			return "parseFloat error:" + inString
+		}
+		precision := options.Position
+		if precision <= 0 {
+			if index := strings.IndexByte(inString, '.'); index >= 0 {
+				precision = len(inString) - index - 1
+			}
+		}
+		return strconv.FormatFloat(value+noise(), 'f', precision, 64)
+	}
+}
+
+// buildNoiseGenerator returns the zero-mean noise function (the function must not be shared between go-routines)
+func buildNoiseGenerator(options model.AnoOption) (func() float64, error) {
+	epsilon, err := strconv.ParseFloat(options.Epsilon, 64)
+	if err != nil || epsilon <= 0 {
+		return nil, errors.New("epsilon parameter error")
+	}
+	sensitivity, err := strconv.ParseFloat(options.Sensitivity, 64)
+	if err != nil || sensitivity <= 0 {
+		return nil, errors.New("sensitivity parameter error")
+	}
+
+	generator := newNoiseGenerator()
+	switch options.Algorithm {
+	case "laplace":
+		scale := sensitivity / epsilon
+		return func() float64 {
+			for {
+				u := generator.Float64() - 0.5
+				if value := 1 - 2*math.Abs(u); value > 0 {
+					if u < 0 {
+						return scale * math.Log(value)
+					}
+					return -scale * math.Log(value)
+				}
+			}
+		}, nil
+	case "gaussian":
+		// sigma of classic Gaussian mechanism guarantees (epsilon, delta)-DP for epsilon < 1 only
+		if epsilon >= 1 {
+			return nil, errors.New("epsilon parameter error")
+		}
+		delta, err := strconv.ParseFloat(options.Delta, 64)
+		if err != nil || delta <= 0 || delta >= 1 {
+			return nil, errors.New("delta parameter error")
+		}
+		sigma := sensitivity * math.Sqrt(2*math.Log(1.25/delta)) / epsilon
+		return func() float64 {
+			return generator.NormFloat64() * sigma
+		}, nil
+	default:
+		return nil, errors.New("unknown Noise algorithm")
+	}
+}
@@ -0,0 +1,80 @@
+package did
+
+import (
+	"math/rand"
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// zeroFirstSource returns 0 at first (u = -0.5, rejected by laplace sampling) and then the values of the seeded source
+type zeroFirstSource struct {
+	rand.Source
+	used bool
+}
+
+func (s *zeroFirstSource) Int63() int64 {
+	if !s.used {
+		s.used = true
+		return 0
+	}
+	return s.Source.Int63()
+}
+
+func TestNoiseSeeded(t *testing.T) {
+	defer SetNoiseSource(nil)
+	SetNoiseSource(func() rand.Source { return rand.NewSource(1) })
+
+	for _, options := range []model.AnoOption{
+		{Algorithm: "laplace", Epsilon: "1", Sensitivity: "1"},
+		{Algorithm: "gaussian", Epsilon: "0.5", Delta: "0.00001", Sensitivity: "1"},
+	} {
+		first, second := BuildNoiseFunc(options), BuildNoiseFunc(options)
+		for _, value := range []string{"100.00", "5000", "-3.5"} {
+			if output, expected := first(value), second(value); output != expected {
+				t.Errorf("%s: %q is not deterministic (%q, %q)", options.Algorithm, value, output, expected)
+			}
+		}
+		if output := first("100.00"); len(output) < 4 || output[len(output)-3] != '.' {
+			t.Errorf("%s: precision is not kept (%q)", options.Algorithm, output)
+		}
+	}
+}
+
+func TestNoiseLaplaceRejection(t *testing.T) {
+	defer SetNoiseSource(nil)
+	options := model.AnoOption{Algorithm: "laplace", Epsilon: "1", Sensitivity: "1"}
+
+	SetNoiseSource(func() rand.Source { return rand.NewSource(1) })
+	expected := BuildNoiseFunc(options)("100.0000")
+	SetNoiseSource(func() rand.Source { return &zeroFirstSource{Source: rand.NewSource(1)} })
+	if output := BuildNoiseFunc(options)("100.0000"); output != expected {
+		t.Errorf("rejected sample is used (%q, expected %q)", output, expected)
+	}
+}
+
+func TestNoiseParameterError(t *testing.T) {
+	for _, test := range []struct {
+		options  model.AnoOption
+		expected string
+	}{
+		{model.AnoOption{Algorithm: "laplace", Epsilon: "0", Sensitivity: "1"}, "epsilon parameter error"},
+		{model.AnoOption{Algorithm: "laplace", Epsilon: "-1", Sensitivity: "1"}, "epsilon parameter error"},
+		{model.AnoOption{Algorithm: "laplace", Epsilon: "1", Sensitivity: "0"}, "sensitivity parameter error"},
+		{model.AnoOption{Algorithm: "gaussian", Epsilon: "0.5", Sensitivity: "1"}, "delta parameter error"},
+		{model.AnoOption{Algorithm: "gaussian", Epsilon: "0.5", Delta: "0", Sensitivity: "1"}, "delta parameter error"},
+		{model.AnoOption{Algorithm: "gaussian", Epsilon: "0.5", Delta: "1", Sensitivity: "1"}, "delta parameter error"},
+		{model.AnoOption{Algorithm: "gaussian", Epsilon: "1", Delta: "0.00001", Sensitivity: "1"}, "epsilon parameter error"},
+		{model.AnoOption{Algorithm: "uniform", Epsilon: "1", Sensitivity: "1"}, "unknown Noise algorithm"},
+	} {
+		if output := BuildNoiseFunc(test.options)("100"); output != test.expected {
+			t.Errorf("%+v: %q, expected %q", test.options, output, test.expected)
+		}
+	}
+
+	output := BuildNoiseFunc(model.AnoOption{Algorithm: "laplace", Epsilon: "1", Sensitivity: "1"})("abc")
+	if output != "parseFloat error:abc" {
+		t.Errorf("invalid number: %q", output)
+	}
+}
@@ -0,0 +1,170 @@
+package did
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// piiDetector finds a kind of personal information in free text
+type piiDetector struct {
+	pattern  *regexp.Regexp
+	validate func(string) bool
+}
+
+// detectors for Korean personal information (key: detector name)
+var piiDetectors = map[string]piiDetector{
+	// resident registration number (ex. 900101-1234567)
+	"rrn": {regexp.MustCompile(`\d{6}-?[1-4]\d{6}`), isRegistrationNumber},
+	// foreign registration number (ex. 900101-5234567)
+	"frn": {regexp.MustCompile(`\d{6}-?[5-8]\d{6}`), isRegistrationNumber},
+	// mobile number (ex. 010-1234-5678)
+	"mobile": {regexp.MustCompile(`01[016789][-. ]?\d{3,4}[-. ]?\d{4}`), nil},
+	// landline number (ex. 02-123-4567, (031) 123-4567)
+	"landline": {regexp.MustCompile(`\(?0(?:2|[3-6][1-5]|70)\)?[-. ]?\d{3,4}[-. ]?\d{4}`), nil},
+	// email address
+	"email": {regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), nil},
+	// card number (13 ~ 19 digits, Luhn checked)
+	"card": {regexp.MustCompile(`\d(?:[- ]?\d){12,18}`), isCardNumber},
+	// bank account number (hyphenated, 10 ~ 14 digits)
+	"account": {regexp.MustCompile(`\d{2,6}-\d{2,6}-\d{1,7}(?:-\d{1,3})?`), isAccountNumber},
+	// passport number (ex. M12345678, M123A4567)
+	"passport": {regexp.MustCompile(`[MSRODmsrod](?:\d{8}|\d{3}[A-Za-z]\d{4})`), nil},
+}
+
+// mobile number pattern to distinguish from bank account number
+var mobilePattern = regexp.MustCompile(`^01[016789]-\d{3,4}-\d{4}$`)
+
+// 자유 형식의 텍스트에서 개인정보(주민등록번호, 외국인등록번호, 휴대전화번호, 유선전화번호, 이메일, 카드번호, 계좌번호, 여권번호)를 탐지하여 해당 부분만 마스킹하는 함수를 생성하는 함수입니다.
+// Detectors가 지정되지 않은 경우 모든 탐지기를 사용하며, Fore/Aft만 지정된 기존 옵션은 BuildMaskingFunc로 처리됩니다.
+func BuildPiiReductionFunc(options model.AnoOption) func(string) string {
+	// Compatible with masking options
+	if len(options.Detectors) == 0 && (options.Fore != "" || options.Aft != "") {
+		return BuildMaskingFunc(options)
+	}
+
+	// Select detectors
+	names := options.Detectors
+	if len(names) == 0 {
+		names = []string{"rrn", "frn", "mobile", "landline", "email", "card", "account", "passport"}
+	}
+	detectors := make([]piiDetector, len(names))
+	for i, name := range names {
+		detector, ok := piiDetectors[strings.ToLower(name)]
+		if !ok {
+			return func(inString string) string {
+				return "detectors parameter error"
+			}
+		}
+		detectors[i] = detector
+	}
+	maskChar := options.MaskChar
+	if maskChar == "" {
+		maskChar = "*"
+	}
+
+	return func(inString string) string {
+		// Find spans of personal information
+		masked := make([]bool, len(inString))
+		found := false
+		for _, detector := range detectors {
+			for _, span := range detector.pattern.FindAllStringIndex(inString, -1) {
+				if !isTokenBoundary(inString, span[0], span[1]) {
+					continue
+				}
+				if detector.validate != nil && !detector.validate(inString[span[0]:span[1]]) {
+					continue
+				}
+				for i := span[0]; i < span[1]; i++ {
+					masked[i] = true
+				}
+				found = true
+			}
+		}
+		if !found {
+			return inString
+		}
+
+		// Mask letters and digits in spans (separators are kept)
+		var builder strings.Builder
+		for i, char := range inString {
+			if masked[i] && isAlphanumeric(char) {
+				builder.WriteString(maskChar)
+			} else {
+				builder.WriteRune(char)
+			}
+		}
+		return builder.String()
+	}
+}
+
+// isTokenBoundary checks that the span is not a part of longer alphanumeric token
+func isTokenBoundary(inString string, start int, end int) bool {
+	if start > 0 {
+		if char, _ := utf8.DecodeLastRuneInString(inString[:start]); isAlphanumeric(char) {
+			return false
+		}
+	}
+	if end < len(inString) {
+		if char, _ := utf8.DecodeRuneInString(inString[end:]); isAlphanumeric(char) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(char rune) bool {
+	return (char >= '0' && char <= '9') || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func extractDigits(inString string) []int {
+	digits := make([]int, 0, len(inString))
+	for _, char := range inString {
+		if char >= '0' && char <= '9' {
+			digits = append(digits, int(char-'0'))
+		}
+	}
+	return digits
+}
+
+// isRegistrationNumber checks the birth date part (YYMMDD) of registration number
+func isRegistrationNumber(inString string) bool {
+	digits := extractDigits(inString)
+	month := digits[2]*10 + digits[3]
+	day := digits[4]*10 + digits[5]
+	return month >= 1 && month <= 12 && day >= 1 && day <= 31
+}
+
+// isCardNumber checks the length and Luhn checksum of card number
+func isCardNumber(inString string) bool {
+	digits := extractDigits(inString)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	return luhnChecksum(digits) == 0
+}
+
+// isAccountNumber checks the length of account number except mobile number
+func isAccountNumber(inString string) bool {
+	digits := extractDigits(inString)
+	return len(digits) >= 10 && len(digits) <= 14 && !mobilePattern.MatchString(inString)
+}
+
+// luhnChecksum returns the Luhn checksum (0: valid)
+func luhnChecksum(digits []int) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum % 10
+}
@@ -0,0 +1,35 @@
+package did
+
+import (
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestPiiReduction(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		options  model.AnoOption
+		input    string
+		expected string
+	}{
+		{"mobile", model.AnoOption{}, "연락처 010-1234-5678 입니다", "연락처 ***-****-**** 입니다"},
+		{"mobile without separator", model.AnoOption{}, "연락처:01012345678", "연락처:***********"},
+		{"landline", model.AnoOption{}, "(02) 123-4567 / 031-123-4567", "(**) ***-**** / ***-***-****"},
+		{"rrn and email", model.AnoOption{}, "주민번호 900101-1234567, 메일 hong@example.co.kr", "주민번호 ******-*******, 메일 ****@*******.**.**"},
+		{"frn", model.AnoOption{}, "외국인 900101-5234567", "외국인 ******-*******"},
+		{"invalid birth date", model.AnoOption{}, "번호 901399-1234567", "번호 901399-1234567"},
+		{"card (Luhn checked)", model.AnoOption{}, "카드 4111 1111 1111 1111 / 4111 1111 1111 1112", "카드 **** **** **** **** / 4111 1111 1111 1112"},
+		{"account and passport", model.AnoOption{}, "계좌 110-123-456789 여권 M12345678", "계좌 ***-***-****** 여권 *********"},
+		{"part of longer token", model.AnoOption{}, "주문번호 20210101123 / A010-1234-5678", "주문번호 20210101123 / A010-1234-5678"},
+		{"no personal information", model.AnoOption{}, "배송 요청 사항 없음", "배송 요청 사항 없음"},
+		{"selected detector", model.AnoOption{Detectors: []string{"email"}}, "010-1234-5678 a@b.com", "010-1234-5678 *@*.***"},
+		{"mask character", model.AnoOption{Detectors: []string{"MOBILE"}, MaskChar: "#"}, "010-1234-5678", "###-####-####"},
+		{"unknown detector", model.AnoOption{Detectors: []string{"address"}}, "010-1234-5678", "detectors parameter error"},
+	} {
+		if output := BuildPiiReductionFunc(test.options)(test.input); output != test.expected {
+			t.Errorf("%s: %q, expected %q", test.name, output, test.expected)
+		}
+	}
+}
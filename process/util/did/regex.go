@@ -0,0 +1,105 @@
+package did
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// 정규 표현식(Pattern)에 일치하는 부분을 캡처 그룹 규칙(Groups)과 치환 템플릿(Replacement)에 따라 마스킹 또는 치환하는 함수를 생성하는 함수입니다.
+// Groups는 그룹 번호 또는 이름별 규칙(keep, mask, drop)이며, Replacement가 없을 경우 일치한 부분에서 각 그룹만 규칙에 따라 변환됩니다.
+//	ex) Pattern: "([A-Z]+)-(\d+)", Groups: {"2": "mask"} => "AB-1234" -> "AB-****"
+func BuildRegexMaskingFunc(options model.AnoOption) func(string) string {
+	re, err := regexp.Compile(options.Pattern)
+	if err != nil || options.Pattern == "" {
+		return func(inString string) string {
+			return "pattern parameter error"
+		}
+	}
+	maskChar := options.MaskChar
+	if maskChar == "" {
+		maskChar = "*"
+	}
+
+	// Set rules by group index
+	rules := make([]string, re.NumSubexp()+1)
+	for key, rule := range options.Groups {
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			index = re.SubexpIndex(key)
+		}
+		if index < 1 || index >= len(rules) || (rule != "keep" && rule != "mask" && rule != "drop") {
+			return func(inString string) string {
+				return "groups parameter error"
+			}
+		}
+		rules[index] = rule
+	}
+	template := options.Replacement
+
+	return func(inString string) string {
+		var builder strings.Builder
+		last := 0
+		for _, match := range re.FindAllStringSubmatchIndex(inString, -1) {
+			builder.WriteString(inString[last:match[0]])
+			if template != "" {
+				// Expand template by transformed groups
+				src, indexes := transformGroups(inString, match, rules, maskChar)
+				builder.Write(re.ExpandString(nil, template, src, indexes))
+			} else {
+				builder.WriteString(replaceGroups(inString, match, rules, maskChar))
+			}
+			last = match[1]
+		}
+		builder.WriteString(inString[last:])
+		return builder.String()
+	}
+}
+
+// transformGroups creates a source string consisting of transformed groups and their indexes
+func transformGroups(inString string, match []int, rules []string, maskChar string) (string, []int) {
+	var builder strings.Builder
+	indexes := make([]int, len(match))
+	for i := 0; i < len(match)/2; i++ {
+		if match[2*i] < 0 {
+			indexes[2*i], indexes[2*i+1] = -1, -1
+			continue
+		}
+		indexes[2*i] = builder.Len()
+		builder.WriteString(applyGroupRule(inString[match[2*i]:match[2*i+1]], rules[i], maskChar))
+		indexes[2*i+1] = builder.Len()
+	}
+	return builder.String(), indexes
+}
+
+// replaceGroups transforms the (outermost) groups in the matched string
+func replaceGroups(inString string, match []int, rules []string, maskChar string) string {
+	var builder strings.Builder
+	cursor := match[0]
+	for i := 1; i < len(match)/2; i++ {
+		start, end := match[2*i], match[2*i+1]
+		// Skip unmatched or nested group
+		if start < 0 || start < cursor {
+			continue
+		}
+		builder.WriteString(inString[cursor:start])
+		builder.WriteString(applyGroupRule(inString[start:end], rules[i], maskChar))
+		cursor = end
+	}
+	builder.WriteString(inString[cursor:match[1]])
+	return builder.String()
+}
+
+func applyGroupRule(value string, rule string, maskChar string) string {
+	switch rule {
+	case "mask":
+		return strings.Repeat(maskChar, len([]rune(value)))
+	case "drop":
+		return ""
+	default:
+		return value
+	}
+}
@@ -0,0 +1,164 @@
+package did
+
+import (
+	"errors"
+	"sync"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// MethodBuilder creates a de-identification function by options
+type MethodBuilder func(model.AnoOption) (func(string) string, error)
+
+// BuildEnvironment contains resources of current export which are used by built-in methods
+type BuildEnvironment struct {
+	// token mapping storage (tokenization)
+	Vault TokenVault
+	// taxonomy loader (hierarchy)
+	Taxonomy func(name string) (Taxonomy, error)
+	// random value shared by columns of current record (date_generalization)
+	Seed *RecordSeed
+}
+
+// methodEntry is a de-identification method in registry (built-in methods are registered at init)
+type methodEntry struct {
+	builtin bool
+	// builder of registered method (nil: built-in method)
+	builder MethodBuilder
+	build   func(model.AnoParamOption, *BuildEnvironment) (func(string) string, error)
+}
+
+var (
+	registry      = make(map[string]methodEntry)
+	registryMutex = &sync.RWMutex{}
+)
+
+func init() {
+	for name, build := range map[string]func(model.AnoOption) func(string) string{
+		"encryption":             BuildEncryptingFunc,
+		"fpe":                    BuildFormatPreservingFunc,
+		"reversible_encryption":  BuildReversibleEncryptingFunc,
+		"rounding":               BuildRoundingFunc,
+		"data_range":             BuildRangingFunc,
+		"age":                    BuildAgingFunc,
+		"noise":                  BuildNoiseFunc,
+		"aggregate":              BuildAggregatingFunc,
+		"blank_impute":           BuildMaskingFunc,
+		"card_tokenization":      BuildCardTokenizingFunc,
+		"email_pseudonymization": BuildEmailPseudonymizingFunc,
+		"phone_pseudonymization": BuildPhonePseudonymizingFunc,
+		"geo_generalization":     BuildGeoGeneralizingFunc,
+		"ip_anonymization":       BuildIpAnonymizingFunc,
+		"normalization":          BuildNormalizingFunc,
+		"regex_mask":             BuildRegexMaskingFunc,
+		"pii_reduction":          BuildPiiReductionFunc,
+	} {
+		build := build
+		registerBuiltin(name, func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+			return build(option.Options), nil
+		})
+	}
+
+	registerBuiltin("tokenization", func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+		return BuildTokenizingFunc(option.Options, env.Vault), nil
+	})
+	registerBuiltin("date_generalization", func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+		return BuildDateGeneralizingFunc(option.Options, env.Seed), nil
+	})
+	registerBuiltin("hierarchy", func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+		if env.Taxonomy == nil {
+			return nil, errors.New("taxonomy load error")
+		}
+		taxonomy, err := env.Taxonomy(option.Options.Taxonomy)
+		if err != nil {
+			return nil, errors.New("taxonomy load error")
+		}
+		return BuildHierarchyFunc(option.Level, taxonomy), nil
+	})
+	registerBuiltin("non", func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+		return func(inString string) string {
+			return inString
+		}, nil
+	})
+}
+
+func registerBuiltin(name string, build func(model.AnoParamOption, *BuildEnvironment) (func(string) string, error)) {
+	registry[name] = methodEntry{builtin: true, build: build}
+}
+
+// 사용자 정의 비식별 처리 방법을 등록하는 함수입니다. 기본 제공되는 방법 또는 이미 등록된 방법의 이름은 사용할 수 없습니다.
+//
+//	# Parameters
+//	name (string): de-identification method name
+//	builder (MethodBuilder): function to create a de-identification function by options
+func Register(name string, builder MethodBuilder) error {
+	if name == "" || builder == nil {
+		return errors.New("Invalid de-identification method\r\n")
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if entry, exists := registry[name]; exists && entry.builtin {
+		return errors.New("Built-in de-identification method: " + name + "\r\n")
+	} else if exists {
+		return errors.New("Already registered de-identification method: " + name + "\r\n")
+	}
+	registry[name] = methodEntry{
+		builder: builder,
+		build: func(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+			return builder(option.Options)
+		},
+	}
+	return nil
+}
+
+// 등록된 사용자 정의 비식별 처리 방법을 조회하는 함수입니다.
+//
+//	# Parameters
+//	name (string): de-identification method name
+//
+//	# Response
+//	(MethodBuilder): function to create a de-identification function by options
+//	(bool): registered or not
+func LookupMethod(name string) (MethodBuilder, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	entry, exists := registry[name]
+	if !exists || entry.builtin {
+		return nil, false
+	}
+	return entry.builder, true
+}
+
+// 지원되는(기본 제공 또는 등록된) 비식별 처리 방법인지 확인하는 함수입니다.
+//
+//	# Parameters
+//	name (string): de-identification method name
+func IsSupportedMethod(name string) bool {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	_, exists := registry[name]
+	return exists
+}
+
+// 비식별 처리 방법(기본 제공 또는 등록된)에 따라 비식별 처리 함수를 생성하는 함수입니다.
+//
+//	# Parameters
+//	option (model.AnoParamOption): de-identification option (single method, not chained)
+//	env (*BuildEnvironment): resources of current export (vault, taxonomy, random value of record)
+//
+//	# Response
+//	(func(string) string): de-identification function
+func BuildMethodFunc(option model.AnoParamOption, env *BuildEnvironment) (func(string) string, error) {
+	registryMutex.RLock()
+	entry, exists := registry[option.Method]
+	registryMutex.RUnlock()
+	if !exists {
+		return nil, errors.New("unknown de-identification method: " + option.Method)
+	}
+	if env == nil {
+		env = &BuildEnvironment{}
+	}
+	return entry.build(option, env)
+}
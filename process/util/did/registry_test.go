@@ -0,0 +1,55 @@
+package did
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestRegistryDispatch(t *testing.T) {
+	// Built-in methods are dispatched through registry
+	fn, err := BuildMethodFunc(model.AnoParamOption{Method: "normalization", Options: model.AnoOption{Algorithm: "trim,upper"}}, nil)
+	if err != nil || fn(" abc ") != "ABC" {
+		t.Fatalf("built-in method: %v", err)
+	}
+	if err := Register("normalization", func(options model.AnoOption) (func(string) string, error) { return nil, nil }); err == nil {
+		t.Error("built-in method is overridden")
+	}
+
+	// Registered method
+	err = Register("test_reverse", func(options model.AnoOption) (func(string) string, error) {
+		if options.Key == "" {
+			return nil, errors.New("key is required")
+		}
+		return func(inString string) string {
+			runes := []rune(inString)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			return string(runes)
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn, err := BuildMethodFunc(model.AnoParamOption{Method: "test_reverse", Options: model.AnoOption{Key: "k"}}, nil); err != nil || fn("abc") != "cba" {
+		t.Errorf("registered method: %v", err)
+	}
+	if _, err := BuildMethodFunc(model.AnoParamOption{Method: "test_reverse"}, nil); err == nil {
+		t.Error("builder error is ignored")
+	}
+	if _, exists := LookupMethod("normalization"); exists {
+		t.Error("built-in method is looked up as registered method")
+	}
+
+	// Unknown method
+	if _, err := BuildMethodFunc(model.AnoParamOption{Method: "unknown"}, nil); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("unknown method: %v", err)
+	}
+	if _, err := BuildMethodFunc(model.AnoParamOption{Method: "hierarchy", Options: model.AnoOption{Taxonomy: "region"}}, nil); err == nil {
+		t.Error("hierarchy is built without taxonomy loader")
+	}
+}
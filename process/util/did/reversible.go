@@ -0,0 +1,137 @@
+package did
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// AES-GCM 기반의 가역 암호화 함수를 생성하는 함수입니다. 암호화 결과는 nonce와 암호문을 결합한 base64(URL) 문자열이며, Tweak(hex)이 있을 경우 추가 인증 데이터로 사용됩니다.
+func BuildReversibleEncryptingFunc(options model.AnoOption) func(string) string {
+	aead, additional, err := buildAead(options)
+	if err != nil {
+		return func(inString string) string {
+			return err.Error()
+		}
+	}
+
+	return func(inString string) string {
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(inString)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return "nonce generation error"
+		}
+		return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(inString), additional))
+	}
+}
+
+// 가역 비식별 처리(reversible_encryption, fpe)된 값을 원래의 값으로 복원하는 함수를 생성하는 함수입니다. 재식별 처리를 위해서만 사용됩니다.
+//	# Parameters
+//	method (string): de-identification method
+//	options (model.AnoOption): de-identification options used to process the value
+func BuildReIdentifyingFunc(method string, options model.AnoOption) (func(string) (string, error), error) {
+	switch method {
+	case "reversible_encryption":
+		aead, additional, err := buildAead(options)
+		if err != nil {
+			return nil, err
+		}
+		return func(inString string) (string, error) {
+			data, err := base64.RawURLEncoding.DecodeString(inString)
+			if err != nil || len(data) < aead.NonceSize() {
+				return "", errors.New("Invalid token format")
+			}
+			plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], additional)
+			if err != nil {
+				return "", errors.New("Token decryption failed")
+			}
+			return string(plain), nil
+		}, nil
+	case "fpe":
+		alphabet, fpe, err := buildFpeCipher(options)
+		if err != nil {
+			return nil, err
+		}
+		return func(inString string) (string, error) {
+			return transformFormatPreserving(inString, alphabet, fpe.decrypt)
+		}, nil
+	default:
+		return nil, errors.New("Irreversible de-identification method")
+	}
+}
+
+// 컬럼의 비식별 옵션(연쇄 옵션 포함)으로부터 재식별 함수를 생성하는 함수입니다. 연쇄 옵션의 경우 마지막 단계부터 연속된 가역 처리 단계들을 역순으로 복원하며, 가역 처리 이후에 비가역 처리 단계가 있으면 복원할 수 없습니다.
+// 가역 처리 이전의 단계(ex. normalization)는 복원되지 않으므로, 결과는 가역 처리 직전의 값입니다.
+//	# Parameters
+//	option (model.AnoParamOption): de-identification option for column
+func BuildColumnReIdentifyingFunc(option model.AnoParamOption) (func(string) (string, error), error) {
+	steps := FlattenOption(option)
+	// Find trailing reversible steps (steps[first:])
+	first := len(steps)
+	for first > 0 && (isReversibleMethod(steps[first-1].Method) || steps[first-1].Method == "non") {
+		first--
+	}
+	reversible := false
+	for _, step := range steps[first:] {
+		reversible = reversible || isReversibleMethod(step.Method)
+	}
+	if !reversible {
+		for _, step := range steps[:first] {
+			if isReversibleMethod(step.Method) {
+				return nil, errors.New("Irreversible de-identification method is applied after reversible method")
+			}
+		}
+		return nil, errors.New("Irreversible de-identification method")
+	}
+
+	// Build re-identification functions (in reverse order)
+	reIdentifiers := make([]func(string) (string, error), 0, len(steps)-first)
+	for i := len(steps) - 1; i >= first; i-- {
+		if steps[i].Method == "non" {
+			continue
+		}
+		reIdentifier, err := BuildReIdentifyingFunc(steps[i].Method, steps[i].Options)
+		if err != nil {
+			return nil, err
+		}
+		reIdentifiers = append(reIdentifiers, reIdentifier)
+	}
+	return func(inString string) (string, error) {
+		var err error
+		for _, reIdentifier := range reIdentifiers {
+			if inString, err = reIdentifier(inString); err != nil {
+				return "", err
+			}
+		}
+		return inString, nil
+	}, nil
+}
+
+func isReversibleMethod(method string) bool {
+	return method == "reversible_encryption" || method == "fpe"
+}
+
+func buildAead(options model.AnoOption) (cipher.AEAD, []byte, error) {
+	key, err := hex.DecodeString(options.Key)
+	if err != nil {
+		return nil, nil, errors.New("key parameter error")
+	}
+	additional, err := hex.DecodeString(options.Tweak)
+	if err != nil {
+		return nil, nil, errors.New("tweak parameter error")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, errors.New("key parameter error")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, errors.New("key parameter error")
+	}
+	return aead, additional, nil
+}
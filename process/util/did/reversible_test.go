@@ -0,0 +1,64 @@
+package did
+
+import (
+	"strings"
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestReIdentifyChainedColumn(t *testing.T) {
+	encryption := model.AnoParamOption{Method: "reversible_encryption", Options: model.AnoOption{Key: "2b7e151628aed2a6abf7158809cf4f3c"}}
+	fpe := model.AnoParamOption{Method: "fpe", Options: model.AnoOption{Algorithm: "ff1", Key: "2b7e151628aed2a6abf7158809cf4f3c"}}
+	normalization := model.AnoParamOption{Method: "normalization", Options: model.AnoOption{Algorithm: "trim,lower"}}
+
+	for _, test := range []struct {
+		name     string
+		option   model.AnoParamOption
+		input    string
+		expected string
+	}{
+		{"normalized", model.AnoParamOption{Chain: []model.AnoParamOption{normalization, encryption}}, "  Hong@Example.com ", "hong@example.com"},
+		{"fpe and encrypted", model.AnoParamOption{Chain: []model.AnoParamOption{fpe, encryption}}, "010-1234-5678", "010-1234-5678"},
+		{"nested", model.AnoParamOption{Chain: []model.AnoParamOption{{Chain: []model.AnoParamOption{normalization, fpe}}, {Method: "non"}}}, " 0123456789", "0123456789"},
+	} {
+		encrypted := buildChainFunc(test.option)(test.input)
+		reIdentify, err := BuildColumnReIdentifyingFunc(test.option)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if output, err := reIdentify(encrypted); err != nil || output != test.expected {
+			t.Errorf("%s: re-identified %q (%v), expected %q", test.name, output, err, test.expected)
+		}
+	}
+
+	for _, test := range []struct {
+		option   model.AnoParamOption
+		expected string
+	}{
+		{model.AnoParamOption{Chain: []model.AnoParamOption{encryption, normalization}}, "applied after reversible method"},
+		{model.AnoParamOption{Chain: []model.AnoParamOption{normalization, {Method: "encryption", Options: model.AnoOption{Algorithm: "hash(sha256)"}}}}, "Irreversible de-identification method"},
+	} {
+		if _, err := BuildColumnReIdentifyingFunc(test.option); err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%+v: %v, expected %q", test.option, err, test.expected)
+		}
+	}
+}
+
+// buildChainFunc builds de-identification function of test options (normalization and reversible methods only)
+func buildChainFunc(option model.AnoParamOption) func(string) string {
+	steps := FlattenOption(option)
+	funcs := make([]func(string) string, 0, len(steps))
+	for _, step := range steps {
+		switch step.Method {
+		case "normalization":
+			funcs = append(funcs, BuildNormalizingFunc(step.Options))
+		case "fpe":
+			funcs = append(funcs, BuildFormatPreservingFunc(step.Options))
+		case "reversible_encryption":
+			funcs = append(funcs, BuildReversibleEncryptingFunc(step.Options))
+		}
+	}
+	return ChainFuncs(funcs...)
+}
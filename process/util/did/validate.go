@@ -0,0 +1,338 @@
+package did
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// ValidationError describes a malformed de-identification option
+type ValidationError struct {
+	Column  string `json:"column"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Column + "." + e.Field + ": " + e.Message
+}
+
+// 컬럼별 비식별 옵션의 유효성(지원되는 처리 방법, 숫자 형식, 범위, 키 등)을 검증하는 함수입니다. 연쇄(Chain) 옵션은 각 단계별로 검증됩니다.
+//	# Parameters
+//	options (map[string]model.AnoParamOption): de-identification option by column
+//
+//	# Response
+//	([]ValidationError): validation errors (empty: valid)
+func ValidateOptions(options map[string]model.AnoParamOption) []ValidationError {
+	// Sort columns (for deterministic result)
+	columns := make([]string, 0, len(options))
+	for column := range options {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	validator := &optionValidator{}
+	for _, column := range columns {
+		validator.column = column
+		validator.chain(options[column])
+		for _, step := range FlattenOption(options[column]) {
+			validator.validate(step)
+		}
+	}
+	return validator.errors
+}
+
+// optionValidator collects validation errors for column
+type optionValidator struct {
+	column string
+	errors []ValidationError
+}
+
+func (v *optionValidator) add(field string, message string) {
+	v.errors = append(v.errors, ValidationError{Column: v.column, Field: field, Message: message})
+}
+
+// addBuildError adds an error created by option parser (ex. "key parameter error")
+func (v *optionValidator) addBuildError(err error) {
+	message := err.Error()
+	if strings.HasSuffix(message, " parameter error") {
+		v.add(strings.TrimSuffix(message, " parameter error"), "invalid value")
+	} else {
+		v.add("algorithm", message)
+	}
+}
+
+func (v *optionValidator) float(field string, value string) (float64, bool) {
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		v.add(field, "must be a number")
+		return 0, false
+	}
+	return result, true
+}
+
+func (v *optionValidator) integer(field string, value string, min int64, optional bool) (int64, bool) {
+	if optional && value == "" {
+		return 0, true
+	}
+	result, err := strconv.ParseInt(value, 10, 0)
+	if err != nil {
+		v.add(field, "must be an integer")
+		return 0, false
+	} else if result < min {
+		v.add(field, "must be greater than or equal to "+strconv.FormatInt(min, 10))
+		return 0, false
+	}
+	return result, true
+}
+
+func (v *optionValidator) boolean(field string, value string, optional bool) {
+	if optional && value == "" {
+		return
+	}
+	if _, err := strconv.ParseBool(value); err != nil {
+		v.add(field, "must be a boolean")
+	}
+}
+
+func (v *optionValidator) oneOf(field string, value string, candidates ...string) bool {
+	for _, candidate := range candidates {
+		if value == candidate {
+			return true
+		}
+	}
+	v.add(field, "must be one of "+strings.Join(candidates, ", "))
+	return false
+}
+
+func (v *optionValidator) bounds(lowerValue string, upperValue string) {
+	lower, ok := v.float("lower", lowerValue)
+	upper, ok2 := v.float("upper", upperValue)
+	if ok && ok2 && lower >= upper {
+		v.add("upper", "must be greater than lower")
+	}
+}
+
+// chain checks that an option has either a method or chained steps (method of chained option is ignored)
+func (v *optionValidator) chain(option model.AnoParamOption) {
+	if len(option.Chain) == 0 {
+		return
+	}
+	if option.Method != "" {
+		v.add("chain", "must not be used with method")
+	}
+	for _, step := range option.Chain {
+		v.chain(step)
+	}
+}
+
+func (v *optionValidator) validate(option model.AnoParamOption) {
+	options := option.Options
+	switch option.Method {
+	case "encryption":
+		if v.oneOf("algorithm", options.Algorithm, "hmac", "hash(sha256)", "hash(md5)") && options.Algorithm == "hmac" {
+			if options.Key == "" {
+				v.add("key", "HMAC key is required")
+			}
+			v.oneOf("digest", options.Digest, "", "sha256", "md5")
+		}
+	case "fpe":
+		if _, _, err := buildFpeCipher(options); err != nil {
+			v.addBuildError(err)
+		}
+	case "reversible_encryption":
+		if _, _, err := buildAead(options); err != nil {
+			v.addBuildError(err)
+		}
+	case "tokenization":
+		if options.Vault == "" {
+			v.add("vault", "vault name is required")
+		}
+		if key, err := hex.DecodeString(options.Key); err != nil || len(key) == 0 {
+			v.add("key", "must be a hex string")
+		}
+		v.integer("length", options.Length, 1, true)
+		if options.Alphabet != "" && len([]rune(options.Alphabet)) < 2 {
+			v.add("alphabet", "must have at least 2 characters")
+		}
+	case "rounding":
+		v.oneOf("algorithm", options.Algorithm, "round", "ceil", "floor")
+	case "data_range":
+		v.bounds(options.Lower, options.Upper)
+		v.integer("bin", options.Bin, 1, false)
+	case "date_generalization":
+		switch options.Algorithm {
+		case "", "truncate":
+			v.oneOf("unit", options.Unit, "year", "quarter", "month", "week", "day")
+		case "shift":
+			lower, ok := v.integer("lower", options.Lower, math.MinInt32, false)
+			upper, ok2 := v.integer("upper", options.Upper, math.MinInt32, false)
+			if ok && ok2 && lower > upper {
+				v.add("upper", "must be greater than or equal to lower")
+			}
+			if v.oneOf("scope", options.Scope, "", "record", "api") && options.Scope == "api" && options.Key == "" {
+				v.add("key", "key is required for api scope")
+			}
+		default:
+			v.add("algorithm", "must be one of truncate, shift")
+		}
+	case "age":
+		if options.Reference != "" {
+			if _, _, err := parseDate(options.Reference); err != nil {
+				v.add("reference", "must be a date")
+			}
+		}
+		switch options.Algorithm {
+		case "age":
+		case "", "band":
+			v.integer("unit", options.Unit, 1, true)
+		case "range":
+			v.bounds(options.Lower, options.Upper)
+			v.integer("bin", options.Bin, 1, false)
+		default:
+			v.add("algorithm", "must be one of age, band, range")
+		}
+	case "hierarchy":
+		if options.Taxonomy == "" {
+			v.add("taxonomy", "taxonomy name is required")
+		}
+		if option.Level < 0 {
+			v.add("level", "must be greater than or equal to 0")
+		}
+	case "noise":
+		if epsilon, err := strconv.ParseFloat(options.Epsilon, 64); err == nil && epsilon >= 1 && options.Algorithm == "gaussian" {
+			v.add("epsilon", "must be less than 1 for gaussian")
+		} else if _, err := buildNoiseGenerator(options); err != nil {
+			v.addBuildError(err)
+		}
+	case "aggregate":
+		if v.oneOf("algorithm", options.Algorithm, "count", "sum") && options.Epsilon != "" {
+			sensitivity := options.Sensitivity
+			if sensitivity == "" && options.Algorithm == "count" {
+				sensitivity = "1"
+			}
+			if _, err := buildNoiseGenerator(model.AnoOption{Algorithm: "laplace", Epsilon: options.Epsilon, Sensitivity: sensitivity}); err != nil {
+				v.addBuildError(err)
+			}
+		}
+		if options.Threshold != "" {
+			if threshold, ok := v.float("threshold", options.Threshold); ok && threshold < 0 {
+				v.add("threshold", "must be greater than or equal to 0")
+			}
+		}
+	case "ip_anonymization":
+		if _, _, err := parsePrefixes(options); err != nil {
+			v.addBuildError(err)
+		}
+		switch options.Algorithm {
+		case "", "truncate":
+		case "cryptopan":
+			if _, err := newCryptoPan(options.Key); err != nil {
+				v.addBuildError(err)
+			}
+		default:
+			v.add("algorithm", "must be one of truncate, cryptopan")
+		}
+	case "card_tokenization":
+		if key, err := hex.DecodeString(options.Key); err != nil || len(key) == 0 {
+			v.add("key", "must be a hex string")
+		}
+	case "email_pseudonymization":
+		if key, err := hex.DecodeString(options.Key); err != nil || len(key) == 0 {
+			v.add("key", "must be a hex string")
+		}
+		if length, ok := v.integer("length", options.Length, 1, true); ok && length > 64 {
+			v.add("length", "must be less than or equal to 64")
+		}
+		v.boolean("keepDomain", options.KeepDomain, true)
+	case "phone_pseudonymization":
+		if key, err := hex.DecodeString(options.Key); err != nil {
+			v.add("key", "must be a hex string")
+		} else if _, err := aes.NewCipher(key); err != nil {
+			v.add("key", "must be an AES key (16, 24 or 32 bytes)")
+		}
+	case "geo_generalization":
+		v.oneOf("axis", options.Axis, "", "lat", "lng")
+		switch options.Algorithm {
+		case "", "grid":
+			if grid, ok := v.float("grid", options.Grid); ok && grid <= 0 {
+				v.add("grid", "must be greater than 0")
+			}
+		case "geohash", "region":
+			if options.Axis != "" {
+				v.add("axis", "single coordinate column is supported by grid only")
+			}
+			if options.Algorithm == "geohash" && options.Position > 12 {
+				v.add("position", "must be less than or equal to 12")
+			}
+		default:
+			v.add("algorithm", "must be one of grid, geohash, region")
+		}
+	case "normalization":
+		if options.Algorithm != "" {
+			for _, algorithm := range strings.Split(options.Algorithm, ",") {
+				if _, ok := normalizers[strings.TrimSpace(algorithm)]; !ok {
+					v.add("algorithm", "unknown normalization: "+algorithm)
+				}
+			}
+		}
+	case "regex_mask":
+		re, err := regexp.Compile(options.Pattern)
+		if err != nil || options.Pattern == "" {
+			v.add("pattern", "must be a valid regular expression")
+			return
+		}
+		for key, rule := range options.Groups {
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				index = re.SubexpIndex(key)
+			}
+			if index < 1 || index > re.NumSubexp() {
+				v.add("groups", "unknown capture group: "+key)
+			}
+			v.oneOf("groups", rule, "keep", "mask", "drop")
+		}
+	case "pii_reduction":
+		if len(options.Detectors) > 0 || (options.Fore == "" && options.Aft == "") {
+			for _, name := range options.Detectors {
+				if _, ok := piiDetectors[strings.ToLower(name)]; !ok {
+					v.add("detectors", "unknown detector: "+name)
+				}
+			}
+			return
+		}
+		v.validateMasking(options)
+	case "blank_impute":
+		v.validateMasking(options)
+	case "non":
+	default:
+		// Registered method
+		if builder, exists := LookupMethod(option.Method); exists {
+			if _, err := builder(options); err != nil {
+				v.add("options", err.Error())
+			}
+			return
+		}
+		v.add("method", "unknown de-identification method: "+option.Method)
+	}
+}
+
+func (v *optionValidator) validateMasking(options model.AnoOption) {
+	if options.Profile != "" {
+		v.oneOf("profile", options.Profile, "name", "email", "address")
+		v.integer("fore", options.Fore, 0, true)
+		v.integer("aft", options.Aft, 0, true)
+		v.boolean("keepLength", options.KeepLength, true)
+		return
+	}
+	v.integer("fore", options.Fore, 0, false)
+	v.integer("aft", options.Aft, 0, false)
+	v.boolean("keepLength", options.KeepLength, false)
+}
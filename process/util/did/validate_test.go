@@ -0,0 +1,49 @@
+package did
+
+import (
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+func TestValidateChain(t *testing.T) {
+	trim := model.AnoParamOption{Method: "normalization", Options: model.AnoOption{Algorithm: "trim"}}
+	for _, test := range []struct {
+		option   model.AnoParamOption
+		expected []ValidationError
+	}{
+		{model.AnoParamOption{Chain: []model.AnoParamOption{trim, trim}}, nil},
+		{model.AnoParamOption{Method: "normalization", Chain: []model.AnoParamOption{trim}}, []ValidationError{{Column: "name", Field: "chain", Message: "must not be used with method"}}},
+		{model.AnoParamOption{Chain: []model.AnoParamOption{{Method: "normalization", Chain: []model.AnoParamOption{trim}}}}, []ValidationError{{Column: "name", Field: "chain", Message: "must not be used with method"}}},
+	} {
+		errs := ValidateOptions(map[string]model.AnoParamOption{"name": test.option})
+		if len(errs) != len(test.expected) {
+			t.Errorf("%+v: %v, expected %v", test.option, errs, test.expected)
+			continue
+		}
+		for i := range errs {
+			if errs[i] != test.expected[i] {
+				t.Errorf("%+v: %v, expected %v", test.option, errs[i], test.expected[i])
+			}
+		}
+	}
+}
+
+func TestValidateGaussianEpsilon(t *testing.T) {
+	for _, test := range []struct {
+		epsilon  string
+		expected int
+	}{
+		{"0.5", 0},
+		{"1", 1},
+		{"2", 1},
+	} {
+		errs := ValidateOptions(map[string]model.AnoParamOption{
+			"salary": {Method: "noise", Options: model.AnoOption{Algorithm: "gaussian", Epsilon: test.epsilon, Delta: "0.00001", Sensitivity: "1"}},
+		})
+		if len(errs) != test.expected || (len(errs) > 0 && errs[0].Field != "epsilon") {
+			t.Errorf("epsilon %s: %v", test.epsilon, errs)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package did
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strconv"
+	"sync"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+const (
+	defaultTokenAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	defaultTokenLength   = 16
+	tokenCacheSize       = 10000
+	tokenRetryCount      = 5
+)
+
+// 생성한 후보 토큰이 이미 다른 값에 할당되어 있을 경우 TokenVault가 반환하는 에러입니다.
+var ErrTokenCollision = errors.New("Token collision")
+
+// 원본 값(digest)과 가명 토큰의 매핑을 저장하는 저장소 인터페이스입니다.
+type TokenVault interface {
+	// digest에 매핑된 토큰을 반환하며, 매핑이 없을 경우 candidate를 저장한 후 반환합니다.
+	Tokenize(vault string, digest string, candidate string) (string, error)
+}
+
+var (
+	tokenCaches     = make(map[string]*lruCache)
+	tokenCacheMutex = &sync.Mutex{}
+)
+
+// 가명 토큰 저장소(vault)를 이용하여 값을 무작위 토큰으로 대체하는 함수를 생성하는 함수입니다. 동일한 값은 항상 동일한 토큰으로 대체되며, 조회 결과는 vault 별 LRU 캐시에 저장됩니다.
+// 저장소에는 원본 값 대신 Key(hex) 기반 HMAC digest가 저장되므로, 저장소를 조회할 수 있더라도 Key 없이는 원본 값을 대입하여 확인할 수 없습니다.
+//	# Parameters
+//	options (model.AnoOption): tokenization options (vault, key, length, alphabet)
+//	vault (TokenVault): token mapping storage
+func BuildTokenizingFunc(options model.AnoOption, vault TokenVault) func(string) string {
+	if options.Vault == "" {
+		return func(inString string) string {
+			return "vault parameter error"
+		}
+	}
+	key, err := hex.DecodeString(options.Key)
+	if err != nil || len(key) == 0 {
+		return func(inString string) string {
+			return "key parameter error"
+		}
+	}
+	length := defaultTokenLength
+	if options.Length != "" {
+		value, err := strconv.ParseInt(options.Length, 10, 0)
+		if err != nil || value <= 0 {
+			return func(inString string) string {
+				return "length parameter error"
+			}
+		}
+		length = int(value)
+	}
+	alphabet := []rune(defaultTokenAlphabet)
+	if options.Alphabet != "" {
+		alphabet = []rune(options.Alphabet)
+	}
+	if len(alphabet) < 2 {
+		return func(inString string) string {
+			return "alphabet parameter error"
+		}
+	}
+	cache := getTokenCache(options.Vault)
+
+	return func(inString string) string {
+		if inString == "" {
+			return ""
+		}
+		// Create keyed digest so that the original value is not stored (nor guessable) in vault
+		digest := pseudonymizeHex(key, options.Vault, inString)
+		if token, ok := cache.get(digest); ok {
+			return token
+		}
+
+		for i := 0; i < tokenRetryCount; i++ {
+			candidate, err := createRandomToken(alphabet, length)
+			if err != nil {
+				return "token generation error"
+			}
+			token, err := vault.Tokenize(options.Vault, digest, candidate)
+			if err == ErrTokenCollision {
+				continue
+			} else if err != nil {
+				return "tokenization error"
+			}
+			cache.add(digest, token)
+			return token
+		}
+		return "tokenization error"
+	}
+}
+
+func getTokenCache(vault string) *lruCache {
+	tokenCacheMutex.Lock()
+	defer tokenCacheMutex.Unlock()
+
+	cache, ok := tokenCaches[vault]
+	if !ok {
+		cache = newLruCache(tokenCacheSize)
+		tokenCaches[vault] = cache
+	}
+	return cache
+}
+
+func createRandomToken(alphabet []rune, length int) (string, error) {
+	token := make([]rune, length)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range token {
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		token[i] = alphabet[index.Int64()]
+	}
+	return string(token), nil
+}
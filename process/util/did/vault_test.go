@@ -0,0 +1,45 @@
+package did
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	// Model
+	model "github.com/tovdata/privacydam-go/core/model"
+)
+
+// memoryVault stores token mappings in memory
+type memoryVault map[string]string
+
+func (v memoryVault) Tokenize(vault string, digest string, candidate string) (string, error) {
+	if token, ok := v[vault+"/"+digest]; ok {
+		return token, nil
+	}
+	v[vault+"/"+digest] = candidate
+	return candidate, nil
+}
+
+func TestTokenizingKeyedDigest(t *testing.T) {
+	vault := memoryVault{}
+	tokenize := BuildTokenizingFunc(model.AnoOption{Vault: "keyed", Key: "2b7e151628aed2a6abf7158809cf4f3c"}, vault)
+	token := tokenize("홍길동")
+	if token == "" || tokenize("홍길동") != token {
+		t.Fatalf("token is not stable (%q)", token)
+	}
+
+	// Vault must not contain a public hash of the value
+	sum := sha256.Sum256([]byte("keyed" + "\x00" + "홍길동"))
+	if _, ok := vault["keyed/"+hex.EncodeToString(sum[:])]; ok {
+		t.Error("unkeyed digest is stored")
+	}
+	if _, ok := vault["keyed/"+pseudonymizeHex([]byte("other"), "keyed", "홍길동")]; ok {
+		t.Error("digest does not depend on key")
+	}
+
+	for _, key := range []string{"", "not hex"} {
+		if output := BuildTokenizingFunc(model.AnoOption{Vault: "keyed", Key: key}, vault)("홍길동"); output != "key parameter error" {
+			t.Errorf("key %q: %q", key, output)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package kAno
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+func (t *AnoTester) SetSensitiveField(index int, lValue int, tValue float64, ordered bool, entropy bool) {
+	if index < 0 || index >= t.fieldLen {
+		return
+	}
+	t.sensitiveField = index
+	t.sensitiveDict = make(map[string]map[string]int)
+	t.targetLValue = lValue
+	t.targetTValue = tValue
+	t.orderedValue = ordered
+	t.entropyDiversity = entropy
+}
+func (t *AnoTester) GetSensitiveField() int {
+	return t.sensitiveField
+}
+func (t *AnoTester) addSensitive(key string, value string) {
+	distribution, ok := t.sensitiveDict[key]
+	if !ok {
+		distribution = make(map[string]int)
+		t.sensitiveDict[key] = distribution
+	}
+	distribution[value]++
+}
+
+// EvalSensitive evaluates distinct l-diversity, entropy l-diversity (exp(entropy)) and t-closeness of the sensitive field
+// (suppression: "drop" excludes and "blank" merges classes smaller than target k)
+func (t *AnoTester) EvalSensitive(suppression string) (bool, int, float64, float64) {
+	if t.sensitiveField < 0 {
+		return true, 0, 0, 0
+	}
+
+	// Select equivalence classes and overall distribution
+	classes := make([]map[string]int, 0, len(t.sensitiveDict))
+	overall := make(map[string]int)
+	merged := make(map[string]int)
+	for key, distribution := range t.sensitiveDict {
+		if suppression != "" && t.finalEncoder.freqDict[key] < t.targetKValue {
+			if suppression == "blank" {
+				for value, count := range distribution {
+					merged[value] += count
+					overall[value] += count
+				}
+			}
+			continue
+		}
+		classes = append(classes, distribution)
+		for value, count := range distribution {
+			overall[value] += count
+		}
+	}
+	if len(merged) > 0 {
+		classes = append(classes, merged)
+	}
+	if len(classes) == 0 {
+		return true, 0, 0, 0
+	}
+	values := t.sortValues(overall)
+
+	minDistinct, minEntropy, maxDistance := math.MaxInt32, math.Inf(1), 0.0
+	for _, distribution := range classes {
+		if len(distribution) < minDistinct {
+			minDistinct = len(distribution)
+		}
+		if entropy := entropyDiversity(distribution); entropy < minEntropy {
+			minEntropy = entropy
+		}
+		var distance float64
+		if t.orderedValue {
+			distance = orderedDistance(distribution, overall, values)
+		} else {
+			distance = variationalDistance(distribution, overall, values)
+		}
+		if distance > maxDistance {
+			maxDistance = distance
+		}
+	}
+
+	passed := true
+	if t.targetLValue > 0 {
+		if t.entropyDiversity {
+			passed = minEntropy >= float64(t.targetLValue)
+		} else {
+			passed = minDistinct >= t.targetLValue
+		}
+	}
+	if t.targetTValue > 0 && maxDistance > t.targetTValue {
+		passed = false
+	}
+	return passed, minDistinct, minEntropy, maxDistance
+}
+
+// sortValues sorts values of the sensitive field (numerically if all values are numbers)
+func (t *AnoTester) sortValues(overall map[string]int) []string {
+	values := make([]string, 0, len(overall))
+	numbers := make(map[string]float64, len(overall))
+	numeric := true
+	for value := range overall {
+		values = append(values, value)
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			numbers[value] = number
+		} else {
+			numeric = false
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if numeric {
+			return numbers[values[i]] < numbers[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	return values
+}
+
+func total(distribution map[string]int) float64 {
+	sum := 0
+	for _, count := range distribution {
+		sum += count
+	}
+	return float64(sum)
+}
+
+// entropyDiversity returns exp(entropy) of the distribution
+func entropyDiversity(distribution map[string]int) float64 {
+	sum := total(distribution)
+	entropy := 0.0
+	for _, count := range distribution {
+		p := float64(count) / sum
+		entropy -= p * math.Log(p)
+	}
+	return math.Exp(entropy)
+}
+
+// variationalDistance returns the EMD with equal ground distance (for categorical values)
+func variationalDistance(distribution map[string]int, overall map[string]int, values []string) float64 {
+	sum, overallSum := total(distribution), total(overall)
+	distance := 0.0
+	for _, value := range values {
+		distance += math.Abs(float64(distribution[value])/sum - float64(overall[value])/overallSum)
+	}
+	return distance / 2
+}
+
+// orderedDistance returns the EMD with ordered distance (for numerical or ordinal values)
+func orderedDistance(distribution map[string]int, overall map[string]int, values []string) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	sum, overallSum := total(distribution), total(overall)
+	distance, cumulative := 0.0, 0.0
+	for _, value := range values {
+		cumulative += float64(distribution[value])/sum - float64(overall[value])/overallSum
+		distance += math.Abs(cumulative)
+	}
+	return distance / float64(len(values)-1)
+}
@@ -70,6 +70,16 @@ type AnoTester struct {
 	targetKValue int
 	fieldLen     int
 	evalFields   []bool
+	// sensitive field for l-diversity and t-closeness (-1: not evaluated)
+	sensitiveField int
+	sensitiveDict  map[string]map[string]int
+	targetLValue   int
+	targetTValue   float64
+	orderedValue   bool
+	// entropy l-diversity (false: distinct l-diversity)
+	entropyDiversity bool
+	// sample size / population size for re-identification risk
+	samplingFraction float64
 }
 
 func (t *AnoTester) New(length int, kValue int) {
@@ -86,6 +96,7 @@ func (t *AnoTester) New(length int, kValue int) {
 	}
 	t.finalEncoder.init()
 	t.targetKValue = kValue
+	t.sensitiveField = -1
 }
 func (t *AnoTester) SetEvalFields(fields []bool) {
 	for i, v := range fields {
@@ -95,6 +106,40 @@ func (t *AnoTester) SetEvalFields(fields []bool) {
 	}
 }
 func (t *AnoTester) AddStrings(strList []string) int {
+	key := t.classKey(strList)
+	if t.sensitiveField >= 0 && t.sensitiveField < len(strList) {
+		t.addSensitive(key, strList[t.sensitiveField])
+	}
+	return t.finalEncoder.add(key)
+}
+func (t *AnoTester) Frequency(strList []string) int {
+	// size of equivalence class (after all rows are added)
+	return t.finalEncoder.freqDict[t.classKey(strList)]
+}
+func (t *AnoTester) GetTargetKValue() int {
+	return t.targetKValue
+}
+func (t *AnoTester) GetRowCount() int {
+	count := 0
+	for _, value := range t.finalEncoder.freqDict {
+		count += value
+	}
+	return count
+}
+func (t *AnoTester) GetSuppressionCount() int {
+	// count of rows in equivalence classes smaller than target k
+	count := 0
+	for _, value := range t.finalEncoder.freqDict {
+		if value < t.targetKValue {
+			count += value
+		}
+	}
+	return count
+}
+func (t *AnoTester) IsEvalField(index int) bool {
+	return index < len(t.evalFields) && t.evalFields[index]
+}
+func (t *AnoTester) classKey(strList []string) string {
 	// disable field-level encoding for performance
 	//encoded := make([]int, 0)
 	filtered := make([]string, t.fieldLen)
@@ -107,8 +152,8 @@ func (t *AnoTester) AddStrings(strList []string) int {
 		}
 	}
 	//fmt.Printf("%v\n", encoded)
-	//return t.finalEncoder.add(fmt.Sprintf("%v", encoded))
-	return t.finalEncoder.add(fmt.Sprintf("%q", filtered))
+	//return fmt.Sprintf("%v", encoded)
+	return fmt.Sprintf("%q", filtered)
 }
 func (t *AnoTester) Eval() (bool, int) {
 	actValue := t.finalEncoder.getMinFreq()
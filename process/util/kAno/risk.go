@@ -0,0 +1,87 @@
+package kAno
+
+import (
+	"math"
+	"strconv"
+
+	// Model
+	"github.com/tovdata/privacydam-go/core/model"
+)
+
+// lower bounds of class size histogram buckets
+var riskBuckets = []int{1, 2, 3, 5, 10, 20, 50, 100}
+
+func (t *AnoTester) SetSamplingFraction(samplingFraction float64) {
+	t.samplingFraction = samplingFraction
+}
+
+// EvalRisk evaluates re-identification risk by equivalence class sizes
+// (suppression: "drop" excludes and "blank" merges classes smaller than target k)
+func (t *AnoTester) EvalRisk(suppression string) model.RiskReport {
+	samplingFraction := t.samplingFraction
+	if samplingFraction <= 0 || samplingFraction > 1 {
+		samplingFraction = 1
+	}
+	report := model.RiskReport{
+		SamplingFraction: samplingFraction,
+		Histogram:        make([]model.ClassSizeBucket, len(riskBuckets)),
+	}
+	for i, lower := range riskBuckets {
+		report.Histogram[i].Range = strconv.Itoa(lower) + "+"
+		if i < len(riskBuckets)-1 {
+			if upper := riskBuckets[i+1] - 1; upper > lower {
+				report.Histogram[i].Range = strconv.Itoa(lower) + "-" + strconv.Itoa(upper)
+			} else {
+				report.Histogram[i].Range = strconv.Itoa(lower)
+			}
+		}
+	}
+
+	// Collect class sizes
+	sizes := make([]int, 0, len(t.finalEncoder.freqDict))
+	merged := 0
+	for _, size := range t.finalEncoder.freqDict {
+		if suppression != "" && size < t.targetKValue {
+			if suppression == "blank" {
+				merged += size
+			}
+			continue
+		}
+		sizes = append(sizes, size)
+	}
+	if merged > 0 {
+		sizes = append(sizes, merged)
+	}
+	if len(sizes) == 0 {
+		return report
+	}
+
+	minSize := math.MaxInt32
+	for _, size := range sizes {
+		report.Records += int64(size)
+		if size < minSize {
+			minSize = size
+		}
+		if size == 1 {
+			report.UniqueRecords++
+		}
+		for i := len(riskBuckets) - 1; i >= 0; i-- {
+			if size >= riskBuckets[i] {
+				report.Histogram[i].Classes++
+				report.Histogram[i].Records += int64(size)
+				break
+			}
+		}
+	}
+	report.Classes = int64(len(sizes))
+
+	// Prosecutor risk (the target is known to be in the data)
+	report.ProsecutorMax = 1 / float64(minSize)
+	report.ProsecutorAvg = float64(report.Classes) / float64(report.Records)
+	// Journalist risk (population class size estimated by sampling fraction)
+	report.JournalistMax = 1 / math.Max(float64(minSize)/samplingFraction, float64(minSize))
+	report.JournalistAvg = report.ProsecutorAvg * samplingFraction
+	// Marketer risk (expected proportion of records re-identified in population)
+	report.Marketer = report.JournalistAvg
+	return report
+}
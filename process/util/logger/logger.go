@@ -109,6 +109,7 @@ func CreateProcessedFormat(accessor model.Accessor, api model.Api, evaluation mo
 			KAnoValue: strconv.FormatInt(evaluation.Value, 10),
 			Params:    buffer.String(),
 			Syntax:    api.QueryContent.Syntax,
+			Risk:      evaluation.Risk,
 		},
 	}
 